@@ -0,0 +1,266 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// byteUnits contains the multipliers of the byte size units
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// ArgDuration returns the value of the given subcommand arg as a duration (i.e. 30s, 1h15m)
+func (cl Cli) ArgDuration(name string) (time.Duration, error) {
+
+	v, err := cl.argValue(name)
+	if err != nil {
+		return 0, err
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q for %s (i.e. 30s, 1h15m)", v, flagName(name))
+	}
+
+	return d, nil
+}
+
+// ArgBytes returns the value of the given subcommand arg as a number of bytes
+// Supported units are B, KB, MB, GB, TB (powers of 1000) and KiB, MiB, GiB, TiB (powers of 1024)
+func (cl Cli) ArgBytes(name string) (int64, error) {
+
+	v, err := cl.argValue(name)
+	if err != nil {
+		return 0, err
+	}
+
+	// Split the number and the unit
+	i := strings.IndexFunc(v, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(v)
+	}
+	num, unit := v[:i], strings.ToLower(strings.TrimSpace(v[i:]))
+
+	m, ok := byteUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid size unit %q for %s (i.e. 512KB, 10MiB)", v[i:], flagName(name))
+	}
+
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil || n*m > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid size %q for %s (i.e. 512KB, 10MiB)", v, flagName(name))
+	}
+
+	return int64(n * m), nil
+}
+
+// LoadArgsJSON loads the subcommand args from the given flat JSON object
+// String, number and boolean values are merged into SubCommandArgsMap while
+// the args given on the command line take precedence
+func (cl *Cli) LoadArgsJSON(r io.Reader) error {
+
+	var obj map[string]interface{}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil {
+		return fmt.Errorf("invalid JSON args: %s", err)
+	}
+
+	// Sort the keys for a stable error message
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// Convert the values before merging them
+	args := make(map[string]string)
+	for _, k := range keys {
+		switch v := obj[k].(type) {
+		case string:
+			args[k] = v
+		case json.Number:
+			args[k] = v.String()
+		case bool:
+			args[k] = strconv.FormatBool(v)
+		default:
+			return fmt.Errorf("invalid JSON value for %q: must be a string, number or boolean", k)
+		}
+	}
+
+	// Merge the args
+	if cl.SubCommandArgsMap == nil {
+		cl.SubCommandArgsMap = make(map[string]string)
+	}
+	for k, v := range args {
+		if _, ok := cl.SubCommandArgsMap[k]; !ok {
+			if cl.expandArgs {
+				v = os.ExpandEnv(v)
+			}
+			cl.SubCommandArgsMap[k] = v
+		}
+	}
+
+	return nil
+}
+
+// LoadDotEnv loads the flag defaults from the given dotenv file (i.e. .env)
+// Each `KEY=VALUE` line sets the default of the flag that the key is bound to (see envName)
+// unless the flag is given on the command line. Comments, blank lines and unknown keys are ignored,
+// quotes around the values are trimmed. A missing file is not an error.
+func (cl *Cli) LoadDotEnv(path string) error {
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Bind the flags to the env names
+	flags := make(map[string]*flag.Flag)
+	flag.VisitAll(func(fl *flag.Flag) {
+		flags[envName(fl.Name)] = fl
+	})
+	given := make(map[string]bool)
+	flag.Visit(func(fl *flag.Flag) {
+		given[fl.Name] = true
+	})
+
+	// Iterate lines
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kv := strings.SplitN(strings.TrimPrefix(line, "export "), "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("invalid dotenv line %d: %q", n, line)
+		}
+		key, val := strings.TrimSpace(kv[0]), trimQuotes(strings.TrimSpace(kv[1]))
+
+		fl, ok := flags[key]
+		if !ok || given[fl.Name] {
+			continue
+		}
+		if cl.expandArgs {
+			val = os.ExpandEnv(val)
+		}
+		if err := fl.Value.Set(val); err != nil {
+			return fmt.Errorf("invalid value %q for %s at dotenv line %d: %s", val, key, n, err)
+		}
+		fl.DefValue = val
+		if cl.Flags != nil {
+			cl.Flags[fl.Name] = val
+		}
+	}
+
+	return scanner.Err()
+}
+
+// envName returns the environment variable name of the given flag name (i.e. dry-run is DRY_RUN)
+func envName(name string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
+
+// trimQuotes trims the matching single or double quotes around the given value
+func trimQuotes(val string) string {
+	if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+		return val[1 : len(val)-1]
+	}
+	return val
+}
+
+// ExpandArgs enables or disables expanding the environment variables (i.e. $HOME/data)
+// in the arg values that are loaded from the sources other than the command line
+// such as LoadArgsJSON. Command line args are already expanded by the shell.
+func (cl *Cli) ExpandArgs(enabled bool) {
+	cl.expandArgs = enabled
+}
+
+// Reconstruct returns the command line of the subcommand as args in a deterministic order
+// The subcommand is followed by the flags as `--name=value` and the positional args after `--`
+func (cl Cli) Reconstruct() []string {
+
+	if cl.SubCommand == "" {
+		return nil
+	}
+
+	args := []string{cl.SubCommand}
+	if cl.SubSubCommand != "" {
+		args = append(args, cl.SubSubCommand)
+	}
+	var pos []string
+	if _, ok := cl.flagSets[cl.command()]; ok {
+		// If the args are parsed by a flag set then the flags are rebuilt by their parsed values
+		seen := make(map[string]bool)
+		for _, name := range cl.ArgOrder {
+			if !seen[name] {
+				seen[name] = true
+				args = append(args, flagName(name)+"="+cl.SubCommandArgsMap[name])
+			}
+		}
+		pos = cl.SubCommandPositional
+	} else {
+		for _, tok := range cl.tokenizeArgs(cl.SubCommandArgs) {
+			if !tok.flag {
+				pos = append(pos, tok.name)
+			} else if tok.value != "" {
+				args = append(args, flagName(tok.name)+"="+tok.value)
+			} else {
+				args = append(args, flagName(tok.name))
+			}
+		}
+	}
+
+	if len(pos) > 0 {
+		args = append(args, "--")
+		args = append(args, pos...)
+	}
+
+	return args
+}
+
+// argValue returns the value of the given subcommand arg
+func (cl Cli) argValue(name string) (string, error) {
+
+	v, ok := cl.SubCommandArgsMap[name]
+	if !ok {
+		return "", fmt.Errorf("missing arg %s", flagName(name))
+	}
+	if v == "" {
+		return "", fmt.Errorf("missing value for %s", flagName(name))
+	}
+
+	return v, nil
+}
@@ -0,0 +1,218 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yieldbot/gocli"
+)
+
+func TestArgDuration(t *testing.T) {
+
+	var cli = gocli.Cli{
+		SubCommandArgsMap: map[string]string{
+			"timeout": "1m30s",
+			"bad":     "30 seconds",
+			"empty":   "",
+		},
+	}
+
+	if d, err := cli.ArgDuration("timeout"); err != nil || d != 90*time.Second {
+		t.Error("invalid duration")
+	}
+
+	if _, err := cli.ArgDuration("bad"); err == nil {
+		t.Error("invalid duration error")
+	}
+
+	if _, err := cli.ArgDuration("empty"); err == nil {
+		t.Error("invalid duration error")
+	}
+
+	if _, err := cli.ArgDuration("missing"); err == nil {
+		t.Error("invalid duration error")
+	}
+}
+
+func TestArgBytes(t *testing.T) {
+
+	var cli = gocli.Cli{
+		SubCommandArgsMap: map[string]string{
+			"plain": "512",
+			"kb":    "10KB",
+			"mib":   "1.5 MiB",
+			"gb":    "2gb",
+			"unit":  "10MX",
+			"bad":   "MB",
+		},
+	}
+
+	var sizes = map[string]int64{
+		"plain": 512,
+		"kb":    10000,
+		"mib":   1572864,
+		"gb":    2000000000,
+	}
+	for k, v := range sizes {
+		if n, err := cli.ArgBytes(k); err != nil || n != v {
+			t.Errorf("invalid size for %s", k)
+		}
+	}
+
+	if _, err := cli.ArgBytes("unit"); err == nil {
+		t.Error("invalid size unit error")
+	}
+
+	if _, err := cli.ArgBytes("bad"); err == nil {
+		t.Error("invalid size error")
+	}
+}
+
+func TestLoadArgsJSON(t *testing.T) {
+
+	var cli = gocli.Cli{
+		SubCommandArgsMap: map[string]string{
+			"name": "cmdline",
+		},
+	}
+
+	err := cli.LoadArgsJSON(strings.NewReader(`{"name": "json", "count": 3, "force": true, "path": "/tmp"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var args = map[string]string{
+		"name":  "cmdline",
+		"count": "3",
+		"force": "true",
+		"path":  "/tmp",
+	}
+	for k, v := range args {
+		if cli.SubCommandArgsMap[k] != v {
+			t.Errorf("invalid SubCommandArgsMap arg %s", k)
+		}
+	}
+
+	if err := cli.LoadArgsJSON(strings.NewReader(`{"list": [1, 2]}`)); err == nil {
+		t.Error("invalid JSON value error")
+	}
+	if _, ok := cli.SubCommandArgsMap["list"]; ok {
+		t.Error("invalid SubCommandArgsMap arg list")
+	}
+
+	if err := cli.LoadArgsJSON(strings.NewReader(`[1, 2]`)); err == nil {
+		t.Error("invalid JSON error")
+	}
+}
+
+func TestLoadDotEnv(t *testing.T) {
+
+	f, err := ioutil.TempFile("", "gocli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("# comment\n\nVERSION=\"true\"\nUNKNOWN=foo\n")
+	f.Close()
+
+	// Restore the flag for the other tests
+	fl := flag.Lookup("version")
+	defer func() {
+		fl.Value.Set("false")
+		fl.DefValue = "false"
+	}()
+
+	var cli = gocli.Cli{}
+	if err := cli.LoadDotEnv(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if fl.Value.String() != "true" || fl.DefValue != "true" {
+		t.Error("invalid flag default")
+	}
+
+	if err := cli.LoadDotEnv(f.Name() + ".missing"); err != nil {
+		t.Error("invalid missing file error")
+	}
+
+	ioutil.WriteFile(f.Name(), []byte("VERSION\n"), 0600)
+	if err := cli.LoadDotEnv(f.Name()); err == nil {
+		t.Error("invalid dotenv line error")
+	}
+}
+
+func TestReconstruct(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Commands: map[string]string{
+			"cp": "Copy files",
+		},
+	}
+	cli.ArgAlias("o", "out")
+	cli.InitArgs([]string{"cp", "src", "-o", "x", "--force", "dst", "--mode=644", "-v"})
+
+	rec := cli.Reconstruct()
+	if strings.Join(rec, " ") != "cp --out=x --force=dst --mode=644 -v -- src" {
+		t.Errorf("invalid reconstructed args: %q", rec)
+	}
+
+	// Parse the reconstructed args again
+	var cli2 = gocli.Cli{Commands: cli.Commands}
+	cli2.InitArgs(rec)
+	if strings.Join(cli2.Reconstruct(), " ") != strings.Join(rec, " ") {
+		t.Errorf("invalid reconstructed args: %q", cli2.Reconstruct())
+	}
+
+	cli.InitArgs(nil)
+	if cli.Reconstruct() != nil {
+		t.Error("invalid reconstructed args")
+	}
+
+	// Flag set
+	cli.AddCommand("deploy", "FILE", "Deploy")
+	cli.CommandFlags("deploy").String("env", "", "Env")
+	cli.CommandFlags("deploy").Bool("x", false, "X")
+	cli.InitArgs([]string{"deploy", "-env", "prod", "-x", "file"})
+	if rec = cli.Reconstruct(); strings.Join(rec, " ") != "deploy --env=prod -x=true -- file" {
+		t.Errorf("invalid reconstructed args: %q", rec)
+	}
+	cli.InitArgs(rec)
+	if cli.SubCommandArgsMap["env"] != "prod" || cli.SubCommandArgsMap["x"] != "true" || len(cli.SubCommandPositional) != 1 {
+		t.Errorf("invalid reparsed args: %q %q", cli.SubCommandArgsMap, cli.SubCommandPositional)
+	}
+}
+
+func TestExpandArgs(t *testing.T) {
+
+	os.Setenv("GOCLI_TEST_DIR", "/tmp")
+	defer os.Unsetenv("GOCLI_TEST_DIR")
+
+	var cli = gocli.Cli{
+		SubCommandArgsMap: map[string]string{
+			"cmdline": "$GOCLI_TEST_DIR",
+		},
+	}
+
+	cli.LoadArgsJSON(strings.NewReader(`{"raw": "$GOCLI_TEST_DIR/raw"}`))
+	cli.ExpandArgs(true)
+	cli.LoadArgsJSON(strings.NewReader(`{"path": "$GOCLI_TEST_DIR/data", "cmdline": "x"}`))
+
+	if cli.SubCommandArgsMap["path"] != "/tmp/data" {
+		t.Error("invalid expanded arg")
+	}
+	if cli.SubCommandArgsMap["raw"] != "$GOCLI_TEST_DIR/raw" {
+		t.Error("invalid unexpanded arg")
+	}
+	if cli.SubCommandArgsMap["cmdline"] != "$GOCLI_TEST_DIR" {
+		t.Error("invalid command line arg")
+	}
+}
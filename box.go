@@ -0,0 +1,219 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// boxBorder contains the border drawing characters of a box
+type boxBorder struct {
+	horizontal, vertical                        string
+	topLeft, topRight, bottomLeft, bottomRight  string
+	topTee, bottomTee, leftTee, rightTee, cross string
+}
+
+// lightBorder is the border with the light box drawing characters
+var lightBorder = boxBorder{
+	horizontal:  "─",
+	vertical:    "│",
+	topLeft:     "┌",
+	topRight:    "┐",
+	bottomLeft:  "└",
+	bottomRight: "┘",
+	topTee:      "┬",
+	bottomTee:   "┴",
+	leftTee:     "├",
+	rightTee:    "┤",
+	cross:       "┼",
+}
+
+// headerBorder is the border with the heavy horizontal lines for separating the header
+var headerBorder = boxBorder{
+	horizontal: "━",
+	vertical:   "│",
+	leftTee:    "┝",
+	rightTee:   "┥",
+	cross:      "┿",
+}
+
+// box returns the given lines inside a box that is sized by the longest line
+func (b boxBorder) box(lines []string) string {
+
+	width := 0
+	for _, l := range lines {
+		if n := visibleLen(l); n > width {
+			width = n
+		}
+	}
+
+	out := b.topLeft + strings.Repeat(b.horizontal, width+2) + b.topRight + "\n"
+	for _, l := range lines {
+		out += b.vertical + " " + padRight(l, width) + " " + b.vertical + "\n"
+	}
+	out += b.bottomLeft + strings.Repeat(b.horizontal, width+2) + b.bottomRight
+
+	return out
+}
+
+// rule returns a horizontal line by the given widths, ends and junctions
+func (b boxBorder) rule(widths []int, left, mid, right string) string {
+
+	parts := make([]string, len(widths))
+	for i, w := range widths {
+		parts[i] = strings.Repeat(b.horizontal, w+2)
+	}
+
+	return left + strings.Join(parts, mid) + right
+}
+
+// TableStyle represents the layout style of a table
+type TableStyle int
+
+// Table styles
+const (
+	// StylePlain is the layout of the columns separated by tabs or spaces
+	StylePlain TableStyle = iota
+
+	// StyleBox is the layout of the cells inside the box drawing borders
+	StyleBox
+)
+
+// SetStyle sets the layout style of the table for PrintData and WriteTo (defaults to StylePlain)
+// StyleBox pads the short rows with empty cells and draws a heavier line under the header.
+// Header groups and the header separator are printed by StylePlain only.
+func (t *Table) SetStyle(style TableStyle) error {
+
+	if style != StylePlain && style != StyleBox {
+		return errors.New("invalid table style")
+	}
+	t.style = style
+
+	return nil
+}
+
+// boxWriter renders a table inside the box drawing borders
+type boxWriter struct {
+	t      *Table
+	w      io.Writer
+	rows   [][]string
+	sizes  map[int]int
+	cols   int
+	opened bool
+}
+
+// widths returns the column widths
+func (bw *boxWriter) widths() []int {
+
+	widths := make([]int, bw.cols)
+	for i := range widths {
+		widths[i] = bw.sizes[i]
+	}
+
+	return widths
+}
+
+// line returns the line of the given cells that are padded to the column count
+func (bw *boxWriter) line(cells []string) string {
+
+	parts := make([]string, bw.cols)
+	for i := range parts {
+		var c string
+		if i < len(cells) {
+			c = cells[i]
+		}
+		parts[i] = " " + bw.t.padCell(i, c, bw.sizes[i]) + " "
+	}
+
+	return lightBorder.vertical + strings.Join(parts, lightBorder.vertical) + lightBorder.vertical
+}
+
+// open prints the top border once
+func (bw *boxWriter) open() error {
+
+	if bw.opened {
+		return nil
+	}
+	bw.opened = true
+
+	b := lightBorder
+	_, err := fmt.Fprintln(bw.w, b.rule(bw.widths(), b.topLeft, b.topTee, b.topRight))
+	return err
+}
+
+func (bw *boxWriter) writeHeader(header []string) error {
+
+	if len(header) == 0 {
+		return nil
+	}
+	if err := bw.open(); err != nil {
+		return err
+	}
+
+	b := headerBorder
+	_, err := fmt.Fprintf(bw.w, "%s\n%s\n", bw.line(bw.t.renderHeader()), b.rule(bw.widths(), b.leftTee, b.cross, b.rightTee))
+	return err
+}
+
+func (bw *boxWriter) writeRow(i int, row []string) error {
+
+	// If the row limit is reached then the rest of the rows are summarized by close
+	if limit := bw.t.rowLimit; limit > 0 && i >= limit {
+		return nil
+	}
+	if err := bw.open(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintln(bw.w, bw.line(bw.rows[i]))
+	return err
+}
+
+func (bw *boxWriter) writeFooter(footer []string) error {
+
+	if err := bw.open(); err != nil {
+		return err
+	}
+
+	b := lightBorder
+	_, err := fmt.Fprintf(bw.w, "%s\n%s\n", b.rule(bw.widths(), b.leftTee, b.cross, b.rightTee), bw.line(bw.t.renderLine(footer)))
+	return err
+}
+
+func (bw *boxWriter) close() error {
+
+	if !bw.opened {
+		return nil
+	}
+
+	b := lightBorder
+	if _, err := fmt.Fprintln(bw.w, b.rule(bw.widths(), b.bottomLeft, b.bottomTee, b.bottomRight)); err != nil {
+		return err
+	}
+
+	if limit := bw.t.rowLimit; limit > 0 && len(bw.rows) > limit {
+		if _, err := fmt.Fprintf(bw.w, "… and %d more\n", len(bw.rows)-limit); err != nil {
+			return err
+		}
+	}
+
+	if bw.t.caption == "" {
+		return nil
+	}
+
+	// Find the box width
+	width := 1
+	for _, w := range bw.widths() {
+		width += w + 3
+	}
+
+	_, err := fmt.Fprintln(bw.w, bw.t.alignCaption(width))
+	return err
+}
@@ -0,0 +1,164 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Color represents a terminal color
+type Color int
+
+// Colors
+const (
+	ColorNone Color = iota
+	ColorRed
+	ColorGreen
+	ColorYellow
+	ColorBlue
+	ColorMagenta
+	ColorCyan
+)
+
+// paint returns the given text in the color
+func (c Color) paint(s string) string {
+	if c <= ColorNone || c > ColorCyan {
+		return s
+	}
+	return "\x1b[" + strconv.Itoa(30+int(c)) + "m" + s + "\x1b[0m"
+}
+
+// colorEnabled reports whether the colors can be printed to the given file
+// The colors are disabled if the file is not a terminal or the NO_COLOR environment variable is set
+func colorEnabled(f *os.File) bool {
+	_, noColor := os.LookupEnv("NO_COLOR")
+	return !noColor && isTerminal(f)
+}
+
+// isTerminal reports whether the given file is a terminal
+var isTerminal = func(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0 && os.Getenv("TERM") != "dumb"
+}
+
+// visibleLen returns the number of the visible runes of the given string
+// ANSI escape sequences (i.e. colors) are not counted
+func visibleLen(s string) int {
+
+	n := 0
+	for i := 0; i < len(s); {
+		if l := escapeLen(s[i:]); l > 0 {
+			i += l
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		n++
+	}
+
+	return n
+}
+
+// escapeLen returns the byte length of the ANSI escape sequence at the beginning of the given string
+// It returns zero if the string doesn't start with an escape sequence
+func escapeLen(s string) int {
+
+	if len(s) < 2 || s[0] != '\x1b' {
+		return 0
+	}
+
+	switch s[1] {
+	case '[':
+		// Control sequence, terminated by a byte in the range of @ to ~
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']':
+		// Operating system command, terminated by BEL or ST
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+
+	return 2
+}
+
+// truncateVisible truncates the given string to the given number of visible runes
+// Escape sequences are never split and a reset sequence is appended
+// if the truncated string contains escape sequences
+func truncateVisible(s string, width int) string {
+
+	var n int
+	var escaped bool
+	for i := 0; i < len(s); {
+		if l := escapeLen(s[i:]); l > 0 {
+			i += l
+			escaped = true
+			continue
+		}
+		if n == width {
+			if escaped {
+				return s[:i] + "\x1b[0m"
+			}
+			return s[:i]
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		n++
+	}
+
+	return s
+}
+
+// ellipsis truncates the given string to the given visible width by ending it with an ellipsis
+func ellipsis(s string, width int) string {
+	if visibleLen(s) <= width {
+		return s
+	}
+	return truncateVisible(s, width-1) + "…"
+}
+
+// padRight pads the given string with spaces to the given visible width
+func padRight(s string, width int) string {
+	if n := width - visibleLen(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// padLeft pads the given string with spaces on the left to the given width
+func padLeft(s string, width int) string {
+	if n := width - visibleLen(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
+
+// padCenter centers the given string by padding it with spaces to the given width
+func padCenter(s string, width int) string {
+	if n := width - visibleLen(s); n > 0 {
+		return strings.Repeat(" ", n/2) + s + strings.Repeat(" ", n-n/2)
+	}
+	return s
+}
+
+// hyperlink returns the given text as an OSC 8 terminal hyperlink to the given url
+func hyperlink(text, url string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
@@ -0,0 +1,100 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GenCompletion writes the completion script of the given shell (bash or zsh) to the given writer
+// The script completes the commands and their aliases as the first args of the cli.
+func (cl Cli) GenCompletion(shell string, w io.Writer) error {
+
+	if cl.Name == "" {
+		return errors.New("missing cli name")
+	}
+
+	// Collect the commands and their aliases
+	// The second level commands are completed by their parent commands
+	type completion struct {
+		name, desc string
+	}
+	var comps []completion
+	for _, cn := range cl.commandNames() {
+		if i := strings.Index(cn, " "); i > 0 {
+			if _, ok := cl.Commands[cn[:i]]; !ok && (len(comps) == 0 || comps[len(comps)-1].name != cn[:i]) {
+				comps = append(comps, completion{cn[:i], ""})
+			}
+			continue
+		}
+		comps = append(comps, completion{cn, cl.Commands[cn]})
+		for _, a := range cl.aliasesOf(cn) {
+			comps = append(comps, completion{a, cl.Commands[cn]})
+		}
+	}
+	fn := "_" + shellIdent(cl.Name)
+
+	var script string
+	switch shell {
+	case "bash":
+		names := make([]string, len(comps))
+		for i, c := range comps {
+			names[i] = c.name
+		}
+		script = fmt.Sprintf(`# bash completion for %[1]s
+
+%[2]s_completions() {
+  if [ "$COMP_CWORD" -eq 1 ]; then
+    COMPREPLY=($(compgen -W %[3]s -- "${COMP_WORDS[COMP_CWORD]}"))
+  fi
+}
+
+complete -F %[2]s_completions %[1]s
+`, cl.Name, fn, shellQuote(strings.Join(names, " ")))
+	case "zsh":
+		var lines string
+		for _, c := range comps {
+			lines += "    " + shellQuote(strings.Replace(c.name, ":", "\\:", -1)+":"+c.desc) + "\n"
+		}
+		script = fmt.Sprintf(`#compdef %[1]s
+
+%[2]s() {
+  local -a commands
+  commands=(
+%[3]s  )
+  if (( CURRENT == 2 )); then
+    _describe 'command' commands
+  fi
+}
+
+compdef %[2]s %[1]s
+`, cl.Name, fn, lines)
+	default:
+		return fmt.Errorf("unsupported shell %q; supported shells are bash, zsh", shell)
+	}
+
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+// shellIdent returns the given name as a shell identifier by replacing the invalid characters
+func shellIdent(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// shellQuote returns the given value in single quotes for the shell
+func shellQuote(val string) string {
+	return "'" + strings.Replace(val, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,47 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yieldbot/gocli"
+)
+
+func TestGenCompletion(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Name: "my-app",
+		Commands: map[string]string{
+			"status": "Show the status",
+			"log":    "Show the user's log",
+		},
+	}
+	cli.Alias("st", "status")
+
+	var buf bytes.Buffer
+	if err := cli.GenCompletion("bash", &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "compgen -W 'log status st' --") || !strings.HasSuffix(buf.String(), "complete -F _my_app_completions my-app\n") {
+		t.Errorf("invalid bash completion: %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := cli.GenCompletion("zsh", &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "#compdef my-app\n") || !strings.Contains(buf.String(), "    'log:Show the user'\\''s log'\n    'status:Show the status'\n    'st:Show the status'\n") {
+		t.Errorf("invalid zsh completion: %s", buf.String())
+	}
+
+	if err := cli.GenCompletion("fish", &buf); err == nil || err.Error() != `unsupported shell "fish"; supported shells are bash, zsh` {
+		t.Errorf("invalid unsupported shell error: %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CommandInfo represents the information of a command
+type CommandInfo struct {
+	// Name is the command name
+	Name string
+
+	// Description is the command description
+	Description string
+
+	// ArgsHint is the args hint of the command (i.e. SRC DST)
+	ArgsHint string
+
+	// Parent is the group of a second-level command (i.e. remote of `remote add`)
+	Parent string
+
+	// Aliases contains the aliases of the command in sorted order
+	Aliases []string
+
+	// RequiredFlags contains the required flags of the command (see RequireFlags)
+	RequiredFlags []string
+}
+
+// FlagInfo represents the information of a flag
+type FlagInfo struct {
+	// Name is the flag name
+	Name string
+
+	// Usage is the flag usage
+	Usage string
+
+	// Default is the default value of the flag
+	Default string
+
+	// Type is the value type of the flag (i.e. string, bool, int, time.Duration)
+	Type string
+}
+
+// Definition represents the definition of a Cli such as its commands and flags
+// It can be compared with an expected definition for testing the command line interface.
+type Definition struct {
+	// Name is the name of the Cli
+	Name string
+
+	// Version is the version of the Cli
+	Version string
+
+	// Description is the description of the Cli
+	Description string
+
+	// Commands contains the commands in sorted order
+	Commands []CommandInfo
+
+	// Flags contains the global flags in sorted order
+	Flags []FlagInfo
+}
+
+// Describe returns the definition of the Cli
+func (cl Cli) Describe() Definition {
+
+	def := Definition{
+		Name:        cl.Name,
+		Version:     cl.Version,
+		Description: cl.Description,
+	}
+	cl.Walk(func(c CommandInfo) {
+		def.Commands = append(def.Commands, c)
+	}, func(f FlagInfo) {
+		def.Flags = append(def.Flags, f)
+	})
+
+	return def
+}
+
+// Walk calls the given functions for each command and each global flag
+// Commands and flags are visited in sorted order and nil functions are skipped
+func (cl Cli) Walk(cmdFn func(CommandInfo), flagFn func(FlagInfo)) {
+
+	// Iterate commands
+	if cmdFn != nil {
+		for _, c := range cl.commandNames() {
+			info := CommandInfo{
+				Name:          c,
+				Description:   cl.Commands[c],
+				ArgsHint:      cl.argsHints[c],
+				Aliases:       cl.aliasesOf(c),
+				RequiredFlags: append([]string(nil), cl.requiredFlags[c]...),
+			}
+			if i := strings.Index(c, " "); i > 0 {
+				info.Parent = c[:i]
+			}
+			cmdFn(info)
+		}
+	}
+
+	// Iterate flags
+	if flagFn != nil {
+		flag.VisitAll(func(f *flag.Flag) {
+
+			// If the flag name starts with `test.` then
+			if strings.Index(f.Name, "test.") == 0 {
+				return
+			}
+
+			flagFn(FlagInfo{
+				Name:    f.Name,
+				Usage:   f.Usage,
+				Default: f.DefValue,
+				Type:    flagType(f),
+			})
+		})
+	}
+}
+
+// flagType returns the value type of the given flag
+func flagType(f *flag.Flag) string {
+	if g, ok := f.Value.(flag.Getter); ok {
+		return fmt.Sprintf("%T", g.Get())
+	}
+	return "string"
+}
+
+// GenManPage writes the usage as a roff formatted section 1 man page to the given writer
+func (cl Cli) GenManPage(w io.Writer) error {
+
+	var buf bytes.Buffer
+
+	// Title and name
+	fmt.Fprintf(&buf, ".TH %s 1 \"\" \"%s\"\n", roffEscape(strings.ToUpper(cl.Name)), roffEscape(strings.TrimSpace(cl.Name+" "+cl.Version)))
+	buf.WriteString(".SH NAME\n")
+	if cl.Description != "" {
+		fmt.Fprintf(&buf, "%s \\- %s\n", roffEscape(cl.Name), roffEscape(cl.Description))
+	} else {
+		fmt.Fprintf(&buf, "%s\n", roffEscape(cl.Name))
+	}
+
+	// Synopsis
+	buf.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(&buf, ".B %s\n%s\n", roffEscape(cl.Name), roffEscape(strings.TrimPrefix(cl.usageLine(), cl.Name+" ")))
+
+	// Description
+	if cl.Description != "" {
+		buf.WriteString(".SH DESCRIPTION\n")
+		fmt.Fprintf(&buf, "%s\n", roffEscape(cl.Description))
+	}
+
+	// Options
+	if flagList := usageFlags(flag.CommandLine); len(flagList) > 0 {
+		buf.WriteString(".SH OPTIONS\n")
+		for _, f := range flagList {
+			usage := f.usage
+			if f.defValue != "false" && f.defValue != "" {
+				usage += " (default \"" + f.defValue + "\")"
+			}
+			fmt.Fprintf(&buf, ".TP\n.B %s\n%s\n", roffEscape(f.nameu), roffEscape(usage))
+		}
+	}
+
+	// Commands
+	if len(cl.Commands) > 0 {
+		buf.WriteString(".SH COMMANDS\n")
+		for _, c := range cl.commandNames() {
+			fmt.Fprintf(&buf, ".TP\n.B %s\n%s\n", roffEscape(cl.commandLabel(c)), roffEscape(cl.Commands[c]))
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// GenMarkdownDocs writes the usage as a Markdown reference to the given writer
+// It contains the synopsis, the global flags and a section for each command.
+func (cl Cli) GenMarkdownDocs(w io.Writer) error {
+
+	var buf bytes.Buffer
+
+	// Title and description
+	fmt.Fprintf(&buf, "# %s\n\n", cl.Name)
+	if cl.Description != "" {
+		fmt.Fprintf(&buf, "%s\n\n", cl.Description)
+	}
+
+	// Synopsis
+	fmt.Fprintf(&buf, "## Synopsis\n\n```\n%s\n```\n", cl.usageLine())
+
+	// Options
+	if flagList := usageFlags(flag.CommandLine); len(flagList) > 0 {
+		buf.WriteString("\n## Options\n\n")
+		if err := markdownFlags(flagList).WriteMarkdown(&buf); err != nil {
+			return err
+		}
+	}
+
+	// Commands
+	if len(cl.Commands) > 0 {
+		buf.WriteString("\n## Commands\n")
+		for _, c := range cl.commandNames() {
+			fmt.Fprintf(&buf, "\n### %s\n\n", c)
+			if cl.Commands[c] != "" {
+				fmt.Fprintf(&buf, "%s\n\n", cl.Commands[c])
+			}
+			fmt.Fprintf(&buf, "```\n%s\n```\n", strings.TrimSpace(cl.Name+" "+cl.commandLabel(c)))
+
+			if fs, ok := cl.flagSets[c]; ok {
+				if flagList := usageFlags(fs); len(flagList) > 0 {
+					buf.WriteString("\n")
+					if err := markdownFlags(flagList).WriteMarkdown(&buf); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// markdownFlags returns the table of the given flags for the Markdown docs
+func markdownFlags(flagList []*usageFlag) *Table {
+
+	var table Table
+	table.SetHeader("Flag", "Description", "Default")
+	for i, f := range flagList {
+		def := f.defValue
+		if def == "false" {
+			def = ""
+		}
+		table.AddRow(i+1, "`"+f.nameu+"`", f.usage, def)
+	}
+
+	return &table
+}
+
+// roffEscape escapes the given text for roff
+func roffEscape(s string) string {
+
+	s = strings.Replace(s, "\\", "\\e", -1)
+	s = strings.Replace(s, "-", "\\-", -1)
+
+	// Protect the lines that would be interpreted as requests
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if strings.HasPrefix(l, ".") || strings.HasPrefix(l, "'") {
+			lines[i] = "\\&" + l
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
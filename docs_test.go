@@ -0,0 +1,171 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/yieldbot/gocli"
+)
+
+func ExampleCli_GenManPage() {
+
+	// Init cli
+	var cli = gocli.Cli{
+		Name:        "test",
+		Version:     "1.0.0",
+		Description: "test desc",
+		Commands: map[string]string{
+			"cmd": "Test command",
+		},
+	}
+
+	cli.GenManPage(os.Stdout)
+	// Output:
+	// .TH TEST 1 "" "test 1.0.0"
+	// .SH NAME
+	// test \- test desc
+	// .SH SYNOPSIS
+	// .B test
+	// [OPTIONS] COMMAND [arg...]
+	// .SH DESCRIPTION
+	// test desc
+	// .SH OPTIONS
+	// .TP
+	// .B \-\-arg
+	// Arg flag (default "test")
+	// .TP
+	// .B \-h, \-\-help
+	// Display usage
+	// .TP
+	// .B \-v, \-\-version
+	// Display version information
+	// .SH COMMANDS
+	// .TP
+	// .B cmd
+	// Test command
+}
+
+func ExampleCli_GenMarkdownDocs() {
+
+	// Init cli
+	var cli = gocli.Cli{
+		Name:        "test",
+		Description: "test desc",
+	}
+	cli.AddCommand("cp", "SRC DST", "Copy files")
+	cli.CommandFlags("cp").Bool("r", false, "Copy recursively")
+
+	cli.GenMarkdownDocs(os.Stdout)
+	// Output:
+	// # test
+	//
+	// test desc
+	//
+	// ## Synopsis
+	//
+	// ```
+	// test [OPTIONS] COMMAND [arg...]
+	// ```
+	//
+	// ## Options
+	//
+	// | Flag | Description | Default |
+	// | --- | --- | --- |
+	// | `--arg` | Arg flag | test |
+	// | `-h, --help` | Display usage |   |
+	// | `-v, --version` | Display version information |   |
+	//
+	// ## Commands
+	//
+	// ### cp
+	//
+	// Copy files
+	//
+	// ```
+	// test cp SRC DST
+	// ```
+	//
+	// | Flag | Description | Default |
+	// | --- | --- | --- |
+	// | `-r` | Copy recursively |   |
+}
+
+func TestWalk(t *testing.T) {
+
+	// Init cli
+	var cli = gocli.Cli{
+		Name: "test",
+	}
+	cli.AddCommand("cp", "SRC DST", "Copy files")
+	cli.AddCommand("ls", "", "List files")
+
+	var cmds []gocli.CommandInfo
+	var flags = make(map[string]gocli.FlagInfo)
+	cli.Walk(func(c gocli.CommandInfo) {
+		cmds = append(cmds, c)
+	}, func(f gocli.FlagInfo) {
+		flags[f.Name] = f
+	})
+
+	if len(cmds) != 2 {
+		t.Fatal("invalid commands")
+	}
+	if !reflect.DeepEqual(cmds[0], gocli.CommandInfo{Name: "cp", Description: "Copy files", ArgsHint: "SRC DST"}) {
+		t.Error("invalid command info")
+	}
+	if !reflect.DeepEqual(cmds[1], gocli.CommandInfo{Name: "ls", Description: "List files"}) {
+		t.Error("invalid command info")
+	}
+
+	if len(flags) != 5 {
+		t.Errorf("invalid flags: %v", flags)
+	}
+	if flags["arg"] != (gocli.FlagInfo{Name: "arg", Usage: "Arg flag", Default: "test", Type: "string"}) {
+		t.Error("invalid flag info")
+	}
+	if flags["h"].Type != "bool" {
+		t.Error("invalid flag type")
+	}
+
+	// Nil functions
+	cli.Walk(nil, nil)
+}
+
+func TestDescribe(t *testing.T) {
+
+	// Init cli
+	var cli = gocli.Cli{
+		Name:    "test",
+		Version: "1.0.0",
+	}
+	cli.AddCommand("cp", "SRC DST", "Copy files")
+	cli.AddCommand("remote add", "NAME URL", "Add a remote")
+	cli.Alias("copy", "cp")
+	cli.Alias("c", "cp")
+	cli.RequireFlags("remote add", "fetch")
+
+	def := cli.Describe()
+	var expected = gocli.Definition{
+		Name:    "test",
+		Version: "1.0.0",
+		Commands: []gocli.CommandInfo{
+			{Name: "cp", Description: "Copy files", ArgsHint: "SRC DST", Aliases: []string{"c", "copy"}},
+			{Name: "remote add", Description: "Add a remote", ArgsHint: "NAME URL", Parent: "remote", RequiredFlags: []string{"fetch"}},
+		},
+	}
+	def.Flags = nil
+	if !reflect.DeepEqual(def, expected) {
+		t.Errorf("invalid definition: %+v", def)
+	}
+
+	if def = cli.Describe(); len(def.Flags) != 5 || def.Flags[0].Name != "arg" {
+		t.Errorf("invalid definition flags: %+v", def.Flags)
+	}
+}
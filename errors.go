@@ -0,0 +1,65 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli
+
+import (
+	"errors"
+	"strings"
+)
+
+// Parse errors
+var (
+	// ErrUnknownCommand is the cause of the errors of the unknown commands
+	ErrUnknownCommand = errors.New("unknown command")
+
+	// ErrMissingArg is the cause of the errors of the missing args
+	ErrMissingArg = errors.New("missing arg")
+
+	// ErrInvalidFlag is the cause of the errors of the undefined or invalid flags
+	ErrInvalidFlag = errors.New("invalid flag")
+)
+
+// ParseError represents an error of parsing the command line
+// Err is one of the parse errors (i.e. ErrUnknownCommand) and Name is the offending name.
+type ParseError struct {
+	Err  error
+	Name string
+	msg  string
+}
+
+// Error returns the error message
+func (e *ParseError) Error() string {
+	if e.msg != "" {
+		return e.msg
+	}
+	return e.Err.Error() + ": " + e.Name
+}
+
+// Unwrap returns the cause of the error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// flagErrorName returns the flag name of the given error of the flag package
+func flagErrorName(err error) string {
+
+	msg := err.Error()
+	for _, prefix := range []string{"flag provided but not defined: ", "flag needs an argument: "} {
+		if strings.HasPrefix(msg, prefix) {
+			return strings.TrimLeft(strings.TrimPrefix(msg, prefix), "-")
+		}
+	}
+	if i := strings.Index(msg, " for flag "); i >= 0 {
+		name := msg[i+len(" for flag "):]
+		if j := strings.Index(name, ":"); j >= 0 {
+			name = name[:j]
+		}
+		return strings.TrimLeft(name, "-")
+	}
+
+	return ""
+}
@@ -0,0 +1,46 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli_test
+
+import (
+	"testing"
+
+	"github.com/yieldbot/gocli"
+)
+
+func TestParseError(t *testing.T) {
+
+	var cli = gocli.Cli{}
+	cli.Handle("cmd", "Test command", func(c *gocli.Cli) error {
+		return nil
+	})
+	cli.CommandFlags("cmd").Int("count", 0, "Count")
+
+	cli.InitArgs([]string{"cdm"})
+	err, ok := cli.Run().(*gocli.ParseError)
+	if !ok || err.Err != gocli.ErrUnknownCommand || err.Name != "cdm" || err.Unwrap() != gocli.ErrUnknownCommand {
+		t.Errorf("invalid unknown command error: %v", err)
+	}
+	if err.Error() != `unknown command "cdm"; did you mean "cmd"?` {
+		t.Errorf("invalid error message: %s", err)
+	}
+
+	cli.InitArgs([]string{"cmd", "-bogus"})
+	if err, ok := cli.Run().(*gocli.ParseError); !ok || err.Err != gocli.ErrInvalidFlag || err.Name != "bogus" {
+		t.Errorf("invalid flag error: %v", err)
+	}
+
+	cli.InitArgs([]string{"cmd", "-count", "x"})
+	if err, ok := cli.Run().(*gocli.ParseError); !ok || err.Err != gocli.ErrInvalidFlag || err.Name != "count" {
+		t.Errorf("invalid flag error: %v", err)
+	}
+
+	err = &gocli.ParseError{Err: gocli.ErrMissingArg, Name: "env"}
+	if err.Error() != "missing arg: env" {
+		t.Errorf("invalid error message: %s", err)
+	}
+}
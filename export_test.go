@@ -0,0 +1,23 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli
+
+import (
+	"os"
+)
+
+// SetTerminal sets whether the files are treated as terminals for testing
+// It returns a function that restores the terminal detection
+func SetTerminal(terminal bool) func() {
+	fn := isTerminal
+	isTerminal = func(f *os.File) bool {
+		return terminal
+	}
+	return func() {
+		isTerminal = fn
+	}
+}
@@ -0,0 +1,573 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OneLine returns the given row as a single line of space separated key=value pairs
+// The header is used for the keys
+func (t *Table) OneLine(row int) (string, error) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Check the header and the row number
+	if len(t.header) == 0 {
+		return "", errors.New("missing header")
+	}
+	if row < 1 || row > len(t.data) {
+		return "", errors.New("invalid row index")
+	}
+
+	// Iterate the header and pair the keys with the row values
+	pairs := make([]string, len(t.header))
+	for i, k := range t.header {
+		var v string
+		if i < len(t.data[row-1]) {
+			v = t.data[row-1][i]
+		}
+		pairs[i] = quoteSpaced(k) + "=" + quoteSpaced(v)
+	}
+
+	return strings.Join(pairs, " "), nil
+}
+
+// PrintSections prints each row as a section of aligned `key: value` pairs
+// The section title is the value of the given column and the header is used for the keys
+func (t *Table) PrintSections(titleCol int) error {
+	return t.WriteSections(os.Stdout, titleCol)
+}
+
+// WriteSections writes the sections to the given writer as they're printed by PrintSections
+func (t *Table) WriteSections(w io.Writer, titleCol int) error {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Check the header and the column number
+	if len(t.header) == 0 {
+		return errors.New("missing header")
+	}
+	if titleCol < 1 || titleCol > len(t.header) {
+		return errors.New("invalid column index")
+	}
+
+	// Find the longest key for alignment
+	keylen := 0
+	for i, k := range t.header {
+		if l := visibleLen(k); i != titleCol-1 && l > keylen {
+			keylen = l
+		}
+	}
+
+	// Iterate rows
+	for i, row := range t.data {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+
+		var title string
+		if titleCol <= len(row) {
+			title = row[titleCol-1]
+		}
+		if _, err := fmt.Fprintln(w, title); err != nil {
+			return err
+		}
+
+		for j, k := range t.header {
+			if j == titleCol-1 {
+				continue
+			}
+			var v string
+			if j < len(row) {
+				v = row[j]
+			}
+			if _, err := fmt.Fprintln(w, strings.TrimRight("  "+padRight(k+":", keylen+1)+" "+v, " ")); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// RenderChan renders the rows received from the given channel to the given writer as they arrive
+// The given header (or the table header if it's nil) is rendered first. Column sizes start from
+// the table's and grow by the received rows. It returns when the channel is closed or the context is done.
+func (t *Table) RenderChan(ctx context.Context, w io.Writer, rows <-chan []string, header []string) error {
+
+	// Init the header and the column sizes
+	t.mu.Lock()
+	if header == nil {
+		header = t.header
+	}
+	sizes := make(map[int]int)
+	for k, v := range t.colSizes {
+		sizes[k] = v
+	}
+	t.mu.Unlock()
+
+	grow := func(row []string) {
+		for i, c := range row {
+			if l := visibleLen(c); l > sizes[i] {
+				sizes[i] = l
+			}
+		}
+	}
+
+	// Render header
+	if len(header) > 0 {
+		grow(header)
+		if _, err := fmt.Fprintln(w, t.formatRow(header, sizes)); err != nil {
+			return err
+		}
+	}
+
+	// Render rows
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case row, ok := <-rows:
+			if !ok {
+				return nil
+			}
+			grow(row)
+			if _, err := fmt.Fprintln(w, t.formatRow(row, sizes)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// JoinTablesHorizontal returns the given tables as they are printed by PrintData side by side
+// Each table is padded to its own width, the shorter ones are padded with blank lines and
+// the tables are separated by the given number of spaces. Tabs are expanded by the tab width of 8.
+func JoinTablesHorizontal(gap int, tables ...*Table) string {
+
+	if gap < 0 {
+		gap = 0
+	}
+
+	// Render the tables
+	panels := make([][]string, len(tables))
+	widths := make([]int, len(tables))
+	height := 0
+	for i, t := range tables {
+		var buf bytes.Buffer
+		t.WriteTo(&buf)
+		if buf.Len() == 0 {
+			continue
+		}
+		panels[i] = strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		for j, l := range panels[i] {
+			panels[i][j] = expandTabs(l, 8)
+			if n := visibleLen(panels[i][j]); n > widths[i] {
+				widths[i] = n
+			}
+		}
+		if len(panels[i]) > height {
+			height = len(panels[i])
+		}
+	}
+
+	// Join the lines
+	lines := make([]string, height)
+	sep := strings.Repeat(" ", gap)
+	for j := range lines {
+		for i, panel := range panels {
+			var l string
+			if j < len(panel) {
+				l = panel[j]
+			}
+			if i > 0 {
+				lines[j] += sep
+			}
+			if i < len(panels)-1 {
+				l = padRight(l, widths[i])
+			}
+			lines[j] += l
+		}
+	}
+
+	if height == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// expandTabs replaces the tabs of the given line by the spaces to the next tab stops
+func expandTabs(s string, width int) string {
+
+	if !strings.Contains(s, "\t") {
+		return s
+	}
+
+	var out string
+	for i, part := range strings.Split(s, "\t") {
+		if i > 0 {
+			out += strings.Repeat(" ", width-visibleLen(out)%width)
+		}
+		out += part
+	}
+
+	return out
+}
+
+// WriteCSV writes the header (if any) and the rows to the given writer as comma separated values
+func (t *Table) WriteCSV(w io.Writer) error {
+	return t.RenderAll(map[Format]io.Writer{FormatCSV: w})
+}
+
+// colCount returns the number of the columns by the header and the longest row
+func (t *Table) colCount() int {
+
+	n := len(t.header)
+	if len(t.footer) > n {
+		n = len(t.footer)
+	}
+	for _, row := range t.data {
+		if len(row) > n {
+			n = len(row)
+		}
+	}
+
+	return n
+}
+
+// Format represents an output format of a table
+type Format int
+
+// Formats
+const (
+	// FormatText is the aligned text layout of PrintData
+	FormatText Format = iota
+
+	// FormatCSV is the comma separated values with the header as the first record
+	FormatCSV
+
+	// FormatNDJSON is the newline delimited JSON objects that are keyed by the header
+	FormatNDJSON
+
+	// FormatMarkdown is the GitHub flavored Markdown table with the first row as the header if there is no header
+	FormatMarkdown
+)
+
+// formatWriter represents a writer that renders a table in a format row by row
+type formatWriter interface {
+	writeHeader(header []string) error
+	writeRow(i int, row []string) error
+	writeFooter(footer []string) error
+	close() error
+}
+
+// RenderAll renders the table in each given format to its writer by iterating the data once
+func (t *Table) RenderAll(targets map[Format]io.Writer) error {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.renderAll(targets)
+}
+
+// renderAll renders the table in each given format without locking the table
+func (t *Table) renderAll(targets map[Format]io.Writer) error {
+
+	// Sort the formats for a stable rendering order
+	formats := make([]int, 0, len(targets))
+	for f := range targets {
+		formats = append(formats, int(f))
+	}
+	sort.Ints(formats)
+
+	// Init the writers
+	writers := make([]formatWriter, len(formats))
+	for i, f := range formats {
+		fw, err := t.newFormatWriter(Format(f), targets[Format(f)])
+		if err != nil {
+			return err
+		}
+		writers[i] = fw
+	}
+
+	return t.render(writers)
+}
+
+// render renders the header and the rows by the given writers
+func (t *Table) render(writers []formatWriter) error {
+
+	for _, fw := range writers {
+		if err := fw.writeHeader(t.header); err != nil {
+			return err
+		}
+	}
+	for i, row := range t.data {
+		for _, fw := range writers {
+			if err := fw.writeRow(i, row); err != nil {
+				return err
+			}
+		}
+	}
+	if len(t.footer) > 0 {
+		for _, fw := range writers {
+			if err := fw.writeFooter(t.footer); err != nil {
+				return err
+			}
+		}
+	}
+	for _, fw := range writers {
+		if err := fw.close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newFormatWriter returns the writer of the given format
+func (t *Table) newFormatWriter(f Format, w io.Writer) (formatWriter, error) {
+
+	switch f {
+	case FormatText:
+		rows := t.renderRows(w)
+		if t.style == StyleBox {
+			return &boxWriter{t: t, w: w, rows: rows, sizes: t.renderSizes(rows), cols: t.colCount()}, nil
+		}
+		return &textWriter{t: t, w: w, rows: rows, sizes: t.renderSizes(rows)}, nil
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w), cols: t.colCount()}, nil
+	case FormatNDJSON:
+		if len(t.header) == 0 {
+			return nil, errors.New("missing header")
+		}
+		return &ndjsonWriter{w: w}, nil
+	case FormatMarkdown:
+		return &markdownWriter{t: t, w: w, cols: t.colCount()}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported format %d", f)
+}
+
+// textWriter renders a table as the aligned text layout
+type textWriter struct {
+	t     *Table
+	w     io.Writer
+	rows  [][]string
+	sizes map[int]int
+}
+
+func (tw *textWriter) writeHeader(header []string) error {
+	return tw.t.printHeader(tw.w, tw.sizes)
+}
+
+func (tw *textWriter) writeRow(i int, row []string) error {
+
+	// If the row limit is reached then summarize the rest of the rows
+	if limit := tw.t.rowLimit; limit > 0 && i >= limit {
+		if i == limit {
+			_, err := fmt.Fprintf(tw.w, "… and %d more\n", len(tw.rows)-limit)
+			return err
+		}
+		return nil
+	}
+
+	_, err := fmt.Fprintln(tw.w, tw.t.formatRow(tw.rows[i], tw.sizes))
+	return err
+}
+
+func (tw *textWriter) writeFooter(footer []string) error {
+	return tw.t.printFooter(tw.w, tw.sizes)
+}
+
+func (tw *textWriter) close() error {
+	if tw.t.caption == "" {
+		return nil
+	}
+	_, err := fmt.Fprintln(tw.w, tw.t.formatCaption(tw.sizes))
+	return err
+}
+
+// csvWriter renders a table as comma separated values
+// The records are padded with empty fields to the column count of the table.
+type csvWriter struct {
+	w    *csv.Writer
+	cols int
+}
+
+func (cw *csvWriter) writeHeader(header []string) error {
+	if len(header) == 0 {
+		return nil
+	}
+	return cw.writeRow(-1, header)
+}
+
+func (cw *csvWriter) writeRow(i int, row []string) error {
+	for len(row) < cw.cols {
+		row = append(row[:len(row):len(row)], "")
+	}
+	return cw.w.Write(row)
+}
+
+// writeFooter skips the footer since it can't be told apart from the data records
+func (cw *csvWriter) writeFooter(footer []string) error {
+	return nil
+}
+
+func (cw *csvWriter) close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// WriteNDJSON writes the rows to the given writer as newline delimited JSON objects
+// The header is used for the keys of the objects.
+func (t *Table) WriteNDJSON(w io.Writer) error {
+	return t.RenderAll(map[Format]io.Writer{FormatNDJSON: w})
+}
+
+// ndjsonWriter renders a table as newline delimited JSON objects
+type ndjsonWriter struct {
+	w      io.Writer
+	header []string
+}
+
+func (nw *ndjsonWriter) writeHeader(header []string) error {
+	nw.header = header
+	return nil
+}
+
+func (nw *ndjsonWriter) writeRow(i int, row []string) error {
+
+	// Encode the pairs in the header order
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for col, k := range nw.header {
+		var v string
+		if col < len(row) {
+			v = row[col]
+		}
+		if col > 0 {
+			buf.WriteString(",")
+		}
+		kb, _ := json.Marshal(k)
+		vb, _ := json.Marshal(v)
+		buf.Write(kb)
+		buf.WriteString(":")
+		buf.Write(vb)
+	}
+	buf.WriteString("}\n")
+
+	_, err := nw.w.Write(buf.Bytes())
+	return err
+}
+
+// writeFooter skips the footer since it can't be told apart from the data objects
+func (nw *ndjsonWriter) writeFooter(footer []string) error {
+	return nil
+}
+
+func (nw *ndjsonWriter) close() error {
+	return nil
+}
+
+// WriteMarkdown writes the table to the given writer as a GitHub flavored Markdown table
+// The alignment separator follows the column alignments (see SetColAlign), the pipes are escaped
+// and the empty cells are written as a space.
+func (t *Table) WriteMarkdown(w io.Writer) error {
+	return t.RenderAll(map[Format]io.Writer{FormatMarkdown: w})
+}
+
+// markdownWriter renders a table as a GitHub flavored Markdown table
+// The first row is used as the header if the table has no header.
+type markdownWriter struct {
+	t      *Table
+	w      io.Writer
+	cols   int
+	header bool
+}
+
+func (mw *markdownWriter) writeHeader(header []string) error {
+	if len(header) == 0 {
+		return nil
+	}
+	return mw.writeRow(-1, header)
+}
+
+func (mw *markdownWriter) writeRow(i int, row []string) error {
+
+	if _, err := fmt.Fprintln(mw.w, mw.formatRow(row)); err != nil {
+		return err
+	}
+	if mw.header {
+		return nil
+	}
+	mw.header = true
+
+	// Write the alignment separator after the header
+	seps := make([]string, mw.cols)
+	for col := range seps {
+		switch mw.t.aligns[col] {
+		case AlignRight:
+			seps[col] = "---:"
+		case AlignCenter:
+			seps[col] = ":---:"
+		default:
+			seps[col] = "---"
+		}
+	}
+	_, err := fmt.Fprintln(mw.w, "| "+strings.Join(seps, " | ")+" |")
+	return err
+}
+
+func (mw *markdownWriter) writeFooter(footer []string) error {
+	return mw.writeRow(-1, footer)
+}
+
+func (mw *markdownWriter) close() error {
+	return nil
+}
+
+// formatRow returns the given row as a Markdown table row
+func (mw *markdownWriter) formatRow(row []string) string {
+
+	cells := make([]string, mw.cols)
+	for col := range cells {
+		var v string
+		if col < len(row) {
+			v = strings.Replace(row[col], "|", "\\|", -1)
+			v = strings.Replace(v, "\n", "<br>", -1)
+		}
+		if v == "" {
+			v = " "
+		}
+		cells[col] = v
+	}
+
+	return "| " + strings.Join(cells, " | ") + " |"
+}
+
+// quoteSpaced quotes the given value if it contains spaces or quotes
+func quoteSpaced(val string) string {
+	if strings.ContainsAny(val, " \t\r\n\"") {
+		return strconv.Quote(val)
+	}
+	return val
+}
@@ -0,0 +1,225 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/yieldbot/gocli"
+)
+
+func TestOneLine(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "STATUS")
+	table.AddRow(1, "foo", "up")
+	table.AddRow(2, "bar baz")
+
+	if line, err := table.OneLine(1); err != nil || line != "NAME=foo STATUS=up" {
+		t.Error("invalid one line")
+	}
+
+	if line, err := table.OneLine(2); err != nil || line != `NAME="bar baz" STATUS=` {
+		t.Error("invalid one line")
+	}
+
+	if _, err := table.OneLine(3); err == nil {
+		t.Error("invalid row index error")
+	}
+
+	table = gocli.Table{}
+	table.AddRow(1, "foo", "up")
+	if _, err := table.OneLine(1); err == nil {
+		t.Error("invalid missing header error")
+	}
+}
+
+func ExampleTable_PrintSections() {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "STATUS", "PORT")
+	table.AddRow(1, "foo", "up", "80")
+	table.AddRow(2, "bar", "down")
+
+	table.PrintSections(1)
+	// Output:
+	// foo
+	//   STATUS: up
+	//   PORT:   80
+	//
+	// bar
+	//   STATUS: down
+	//   PORT:
+}
+
+func TestPrintSections(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "foo", "up")
+
+	if err := table.PrintSections(1); err == nil {
+		t.Error("invalid missing header error")
+	}
+
+	table.SetHeader("NAME", "STATUS")
+	if err := table.PrintSections(3); err == nil {
+		t.Error("invalid column index error")
+	}
+
+	var buf bytes.Buffer
+	if err := table.WriteSections(&buf, 2); err != nil || buf.String() != "up\n  NAME: foo\n" {
+		t.Errorf("invalid sections output: %q", buf.String())
+	}
+}
+
+func TestRenderChan(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetTabWidth(1)
+
+	rows := make(chan []string)
+	go func() {
+		rows <- []string{"foo", "1"}
+		rows <- []string{"longer", "2"}
+		close(rows)
+	}()
+
+	var buf bytes.Buffer
+	if err := table.RenderChan(context.Background(), &buf, rows, []string{"NAME", "N"}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "NAME N\nfoo  1\nlonger 2\n" {
+		t.Errorf("invalid table output: %q", buf.String())
+	}
+
+	// Cancel
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := table.RenderChan(ctx, &buf, make(chan []string), nil); err != context.Canceled {
+		t.Error("invalid cancel error")
+	}
+}
+
+func TestRenderAll(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "NOTE")
+	table.AddRow(1, "foo", "a, b")
+	table.AddRow(2, "bar")
+	table.SetTabWidth(1)
+
+	var text, csv bytes.Buffer
+	err := table.RenderAll(map[gocli.Format]io.Writer{
+		gocli.FormatText: &text,
+		gocli.FormatCSV:  &csv,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text.String() != "NAME NOTE\n---- ----\nfoo  a, b\nbar\n" {
+		t.Errorf("invalid text output: %q", text.String())
+	}
+	if csv.String() != "NAME,NOTE\nfoo,\"a, b\"\nbar,\n" {
+		t.Errorf("invalid CSV output: %q", csv.String())
+	}
+
+	if err := table.RenderAll(map[gocli.Format]io.Writer{gocli.Format(-1): &text}); err == nil {
+		t.Error("invalid format error")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetData(1, 1, "foo")
+	table.SetData(3, 3, "say \"hi\"\nbye")
+
+	var buf bytes.Buffer
+	if err := table.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "foo,,\n,,\n,,\"say \"\"hi\"\"\nbye\"\n" {
+		t.Errorf("invalid CSV output: %q", buf.String())
+	}
+	if len(table.Data()[0]) != 1 {
+		t.Error("invalid table data")
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "foo", "say \"hi\"")
+
+	var buf bytes.Buffer
+	if err := table.WriteNDJSON(&buf); err == nil {
+		t.Error("missing header error")
+	}
+
+	table.SetHeader("name", "note", "size")
+	table.AddRow(2, "bar")
+	if err := table.WriteNDJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "{\"name\":\"foo\",\"note\":\"say \\\"hi\\\"\",\"size\":\"\"}\n{\"name\":\"bar\",\"note\":\"\",\"size\":\"\"}\n" {
+		t.Errorf("invalid NDJSON output: %q", buf.String())
+	}
+}
+
+func TestJoinTablesHorizontal(t *testing.T) {
+
+	var left = gocli.Table{}
+	left.SetHeader("NAME", "CPU")
+	left.AddRow(1, "web", "10")
+	left.AddRow(2, "db", "5")
+	left.SetTabWidth(1)
+
+	var right = gocli.Table{}
+	right.SetHeader("DISK")
+	right.AddRow(1, "80%")
+
+	out := gocli.JoinTablesHorizontal(2, &left, &right)
+	if out != "NAME CPU  DISK\n---- ---  ----\nweb  10   80%\ndb   5    \n" {
+		t.Errorf("invalid joined tables: %q", out)
+	}
+
+	if out := gocli.JoinTablesHorizontal(1, &gocli.Table{}); out != "" {
+		t.Errorf("invalid joined tables: %q", out)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "SIZE", "NOTE")
+	table.AddRow(1, "foo", "10", "a|b")
+	table.AddRow(2, "bar")
+	table.SetColAlign(2, gocli.AlignRight)
+	table.SetColAlign(3, gocli.AlignCenter)
+
+	var buf bytes.Buffer
+	if err := table.WriteMarkdown(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "| NAME | SIZE | NOTE |\n| --- | ---: | :---: |\n| foo | 10 | a\\|b |\n| bar |   |   |\n" {
+		t.Errorf("invalid markdown output: %q", buf.String())
+	}
+
+	// Without header
+	table = gocli.Table{}
+	table.AddRow(1, "KEY", "VALUE")
+	table.AddRow(2, "a", "1")
+
+	buf.Reset()
+	table.RenderAll(map[gocli.Format]io.Writer{gocli.FormatMarkdown: &buf})
+	if buf.String() != "| KEY | VALUE |\n| --- | --- |\n| a | 1 |\n" {
+		t.Errorf("invalid markdown output: %q", buf.String())
+	}
+}
@@ -8,14 +8,21 @@
 package gocli
 
 import (
+	"bufio"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Cli represent command line interface
@@ -26,6 +33,12 @@ type Cli struct {
 	// Version is the cli version
 	Version string
 
+	// Commit is the source control commit of the build (i.e. set by -ldflags)
+	Commit string
+
+	// BuildDate is the date of the build (i.e. set by -ldflags)
+	BuildDate string
+
 	// Description is the cli description
 	Description string
 
@@ -35,274 +48,2367 @@ type Cli struct {
 	// SubCommand contains the runtime subcommand
 	SubCommand string
 
+	// SubSubCommand is the second level command of the runtime subcommand (i.e. get of config get)
+	// The second level commands are registered by the compound names (i.e. "config get").
+	SubSubCommand string
+
 	// SubCommandArgs contains the args of the runtime subcommand
 	SubCommandArgs []string
 
 	// SubCommandArgsMap contains the args of the runtime subcommand as mapped
 	SubCommandArgsMap map[string]string
 
+	// SubCommandPositional contains the positional args of the runtime subcommand in order
+	// Flags and their values are excluded
+	SubCommandPositional []string
+
+	// ArgOrder contains the names of the parsed subcommand args in the given order
+	// Repeated args are kept (the last one wins in SubCommandArgsMap)
+	ArgOrder []string
+
 	// Flags contains flags
 	Flags map[string]string
 
+	// Stdout is the writer of the outputs (defaults to os.Stdout)
+	Stdout io.Writer
+
+	// Stderr is the writer of the errors (defaults to os.Stderr)
+	Stderr io.Writer
+
 	// LogOut is logger for stdout
 	LogOut *log.Logger
 
 	// LogErr is logger for stderr
 	LogErr *log.Logger
+
+	// SuggestFlags enables reporting unknown flags with a suggestion and the usage
+	// instead of the default error of the flag package
+	SuggestFlags bool
+
+	// Color enables highlighting the command and option names in the usage
+	// Colors are printed only if Stdout is a terminal and the NO_COLOR environment variable is not set
+	Color bool
+
+	// KeepUsageOrder enables printing the commands in the usage by their registration order
+	// instead of sorting them. The commands of the Commands literal are printed first.
+	// The options are always sorted since the flag package doesn't keep their order.
+	KeepUsageOrder bool
+
+	// Debug enables tracing the parsing and the dispatching to LogErr
+	// It's enabled by a `debug` flag or subcommand arg too
+	Debug bool
+
+	// Timing enables printing the elapsed time from Init to the end of Run to Stderr
+	// It's enabled by a `timing` flag or subcommand arg too
+	Timing bool
+
+	// ExitFunc is the function for exiting the process by RunAndExit, Exit and the flag errors
+	// It defaults to os.Exit and it can be replaced for testing the exit codes.
+	ExitFunc func(int)
+
+	// argsHints contains the args hints of the commands
+	argsHints map[string]string
+
+	// variadics contains the names of the variadic args of the commands
+	variadics map[string]string
+
+	// handlers contains the command handlers
+	handlers map[string]Handler
+
+	// preconditions contains the checks of the commands that run before their handlers
+	preconditions map[string][]func() error
+
+	// metricsHook is called after each command run
+	metricsHook func(command string, dur time.Duration, err error)
+
+	// input is the reader of the interactive helpers
+	input *bufio.Reader
+
+	// argAliases contains the subcommand arg aliases
+	argAliases map[string]string
+
+	// flagNormalizers contains the flag value normalizers
+	flagNormalizers map[string]func(string) string
+
+	// exclusiveFlags contains the mutually exclusive flag groups
+	exclusiveFlags [][]string
+
+	// togetherFlags contains the flag groups that are required together
+	togetherFlags [][]string
+
+	// requiredFlags contains the required flags of the commands
+	requiredFlags map[string][]string
+
+	// expandArgs enables expanding environment variables in the loaded args
+	expandArgs bool
+
+	// noNegativeNumbers disables treating negative numbers as values
+	noNegativeNumbers bool
+
+	// flagSets contains the flag sets of the commands for parsing their args
+	flagSets map[string]*flag.FlagSet
+
+	// argsErr is the error of parsing the subcommand args
+	argsErr error
+
+	// startTime is the time of the initialization for timing
+	startTime time.Time
+
+	// multiCall contains the commands by the invoked names
+	multiCall map[string]string
+
+	// boolFlags contains the boolean subcommand flags which don't take values
+	boolFlags map[string]bool
+
+	// commandOrder contains the command names by their registration order
+	commandOrder []string
+
+	// exitCodes contains the exit codes and their errors in the mapping order
+	// It's a slice since the errors may not be hashable (i.e. a slice of errors).
+	exitCodes []exitCodeMapping
+
+	// commandAliases contains the commands by their aliases
+	commandAliases map[string]string
+
+	// helpRequested is set by the help command or the help flag of the subcommand
+	helpRequested bool
+
+	// helpCommand is the command whose help is requested
+	helpCommand string
+
+	// debugByArgs and timingByArgs are set if Debug and Timing are enabled by the subcommand args
+	// They're reset by InitArgs so the args of an invocation don't affect the next one.
+	debugByArgs  bool
+	timingByArgs bool
+
+	// unknownCommand is the first arg that is not a command nor a flag when there is no command
+	unknownCommand string
 }
 
 // Init initializes Cli instance
+// The errors of the global flags are printed with the usage and the process exits (see InitE).
 func (cl *Cli) Init() {
 
+	cl.startTime = time.Now()
+
+	// Init flag
+	if !flag.Parsed() {
+		if cl.SuggestFlags {
+			cl.parseFlags()
+		} else {
+			flag.Parse()
+		}
+	}
+
+	cl.init()
+}
+
+// InitE initializes Cli instance like Init but returns the errors instead of exiting
+// The errors of the global flags, the unknown commands and the invalid args are returned
+// as ParseError and flag.ErrHelp is returned if the help is requested by the global flags.
+func (cl *Cli) InitE() error {
+
+	cl.startTime = time.Now()
+
 	// Init flag
 	if !flag.Parsed() {
-		flag.Parse()
+		if err := cl.parseGlobalFlags(); err != nil {
+			cl.initWriters()
+			return err
+		}
+	}
+
+	cl.init()
+
+	// Check the command and the args
+	if cl.SubCommand == "" && cl.unknownCommand != "" {
+		return cl.unknownCommandError(cl.unknownCommand)
+	}
+	if cl.argsErr != nil {
+		return &ParseError{Err: ErrInvalidFlag, Name: flagErrorName(cl.argsErr), msg: cl.argsErr.Error()}
+	}
+
+	return nil
+}
+
+// initWriters inits the writers and the loggers
+func (cl *Cli) initWriters() {
+
+	if cl.Stdout == nil {
+		cl.Stdout = os.Stdout
+	}
+	if cl.Stderr == nil {
+		cl.Stderr = os.Stderr
 	}
+	cl.LogOut = log.New(cl.Stdout, "", log.LstdFlags)
+	cl.LogErr = log.New(cl.Stderr, "", log.LstdFlags)
+}
+
+// init initializes Cli instance after the global flags are parsed
+// The args after the global flags are taken whether the flags are parsed by Init or by the caller.
+func (cl *Cli) init() {
 
-	// Init loggers
-	cl.LogOut = log.New(os.Stdout, "", log.LstdFlags)
-	cl.LogErr = log.New(os.Stderr, "", log.LstdFlags)
+	args := flag.Args()
+
+	// Init writers and loggers
+	cl.initWriters()
 
 	// Init flags
 	cl.Flags = make(map[string]string)
 	flag.VisitAll(func(f *flag.Flag) {
 		cl.Flags[f.Name] = f.Value.String()
 	})
+	cl.normalizeFlags()
+
+	// Enable the traces and the timing by the flags
+	if f := flag.Lookup("debug"); f != nil && f.Value.String() == "true" {
+		cl.Debug = true
+	}
+	if f := flag.Lookup("timing"); f != nil && f.Value.String() == "true" {
+		cl.Timing = true
+	}
+	flag.Visit(func(f *flag.Flag) {
+		cl.debugf("flag --%s set to %q", f.Name, f.Value.String())
+	})
 
 	// Init args
-	if len(os.Args) > 1 {
+	// If the flags were parsed before then the global flags are skipped by InitArgs
+	if cmd, ok := cl.multiCall[cl.InvokedName()]; ok {
+		cl.InitArgs(append([]string{cmd}, args...))
+	} else if len(args) > 0 {
+		cl.InitArgs(args)
+	}
+}
 
-		// Iterate the args
-		for _, arg := range os.Args {
-			// If the arg is in command list then
-			if _, ok := cl.Commands[arg]; ok {
-				cl.SubCommand = arg // set as command
-			} else {
-				// Otherwise add it to subcommand args
-				if cl.SubCommand != "" {
-					cl.SubCommandArgs = append(cl.SubCommandArgs, arg)
-				}
-			}
+// InitArgs initializes the subcommand and its args by the given args
+// The args shouldn't contain the program name
+func (cl *Cli) InitArgs(args []string) {
+
+	// Reset the subcommand
+	cl.SubCommand = ""
+	cl.SubSubCommand = ""
+	cl.SubCommandArgs = nil
+	cl.argsErr = nil
+	cl.unknownCommand = ""
+	cl.helpRequested = false
+	cl.helpCommand = ""
+	if cl.debugByArgs {
+		cl.Debug, cl.debugByArgs = false, false
+	}
+	if cl.timingByArgs {
+		cl.Timing, cl.timingByArgs = false, false
+	}
+
+	// Iterate the args
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if c, ok := cl.commandAliases[arg]; ok && cl.SubCommand == "" {
+			arg = c
 		}
+		isCommand := cl.isCommand(arg)
 
-		// Init subcommand args map
-		cl.SubCommandArgsMap = make(map[string]string)
-		var curArg string
-		for _, v := range cl.SubCommandArgs {
-			// If it's an arg then
-			if strings.HasPrefix(v, "-") {
-				curArg = strings.TrimLeft(v, "-")
-				if len(curArg) > 0 {
-					cl.SubCommandArgsMap[curArg] = ""
-				}
-			} else {
-				// Otherwise add it to current arg or add it as arg
-				if len(curArg) > 0 {
-					cl.SubCommandArgsMap[curArg] = v
-					curArg = ""
-				} else {
-					cl.SubCommandArgsMap[v] = ""
-				}
+		// If it's the help command or its arg then
+		if cl.SubCommand == "" && !strings.HasPrefix(arg, "-") && (cl.helpRequested || arg == "help" && !isCommand) {
+			if !cl.helpRequested {
+				cl.helpRequested = true
+			} else if cl.helpCommand == "" {
+				cl.helpCommand = arg
 			}
+			continue
 		}
-	}
-}
 
-// PrintVersion prints version information
-func (cl Cli) PrintVersion(extra bool) {
-	var ver string
+		// If the arg is the first positional one and it's in command list then
+		if isCommand && cl.SubCommand == "" && cl.unknownCommand == "" {
+			cl.SubCommand = arg // set as command
+		} else if _, ok := cl.Commands[cl.SubCommand+" "+arg]; ok && cl.SubCommand != "" && cl.SubSubCommand == "" && len(cl.SubCommandArgs) == 0 {
+			// If it's the second level command of the command then
+			cl.SubSubCommand = arg
+		} else if cl.SubCommand != "" {
+			// Otherwise add it to subcommand args
+			cl.SubCommandArgs = append(cl.SubCommandArgs, arg)
+		} else if globalFlagValue(arg) {
+			// If it's a global flag before the command then skip its value
+			i++
+		} else if !strings.HasPrefix(arg, "-") && cl.unknownCommand == "" && len(cl.Commands) > 0 {
+			// Otherwise keep the first positional arg for reporting the unknown command
+			// The rest of the args are not checked since the command must be the first positional arg
+			cl.unknownCommand = arg
+		}
+	}
+	if cl.SubCommand != "" {
+		cl.unknownCommand = ""
+	}
 
-	if extra == true {
-		ver += fmt.Sprintf("Bin Version : %s\n", strings.TrimPrefix(cl.Version, "v"))
-		ver += fmt.Sprintf("Go version  : %s", runtime.Version())
+	// Init subcommand args map
+	if fs, ok := cl.flagSets[cl.command()]; ok {
+		cl.parseFlagSet(fs)
 	} else {
-		ver = fmt.Sprintf("%s", strings.TrimPrefix(cl.Version, "v"))
+		cl.parseArgs()
 	}
 
-	fmt.Println(ver)
+	// If the help of the subcommand is requested by its flag then
+	_, help := cl.flagArg("help")
+	if v, ok := cl.flagArg("h"); ok && v == "" {
+		help = true
+	}
+	if (help || cl.argsErr == flag.ErrHelp) && cl.SubCommand != "" {
+		cl.helpRequested = true
+		cl.helpCommand = cl.command()
+		cl.argsErr = nil
+	}
+
+	// Enable the traces and the timing by the args
+	if v, ok := cl.flagArg("debug"); ok && v != "false" && !cl.Debug {
+		cl.Debug, cl.debugByArgs = true, true
+	}
+	if v, ok := cl.flagArg("timing"); ok && v != "false" && !cl.Timing {
+		cl.Timing, cl.timingByArgs = true, true
+	}
+	cl.debugf("parsed args %q as command %q with args %q", args, cl.command(), cl.SubCommandArgsMap)
 }
 
-// PrintUsage prints usage info
-// Usage format follows common convention for Go apps
-func (cl Cli) PrintUsage() {
+// globalFlagValue reports whether the given arg is a global flag which is followed by its value
+func globalFlagValue(arg string) bool {
 
-	// Init vars
-	type flagInfo struct {
-		nameu    string
-		name     string
-		usage    string
-		defValue string
+	if !strings.HasPrefix(arg, "-") || strings.Contains(arg, "=") {
+		return false
 	}
 
-	// Find the longest command for alignment
-	maxlen := 0
-	if len(cl.Commands) > 0 {
-		for c := range cl.Commands {
-			if len(c) > maxlen {
-				maxlen = len(c)
-			}
-		}
+	f := flag.Lookup(strings.TrimLeft(arg, "-"))
+	if f == nil {
+		return false
+	}
+	if b, ok := f.Value.(interface {
+		IsBoolFlag() bool
+	}); ok && b.IsBoolFlag() {
+		return false
 	}
 
-	// Iterate flags
-	flagList := make(map[string]*flagInfo)
-	flag.VisitAll(func(f *flag.Flag) {
-
-		// If the flag name starts with `test.` then
-		if strings.Index(f.Name, "test.") == 0 {
-			return
-		}
+	return true
+}
 
-		// Set key by the flag usage for grouping
-		key := fmt.Sprint(f.Usage)
+// debugf prints the given trace to LogErr if the debug mode is enabled
+func (cl Cli) debugf(format string, v ...interface{}) {
+	if cl.Debug && cl.LogErr != nil {
+		cl.LogErr.Printf("debug: "+format, v...)
+	}
+}
 
-		// Init usage name
-		nameu := "-" + f.Name
-		if len(f.Name) > 2 {
-			nameu = "-" + nameu
-		}
+// parseArgs parses the subcommand args into the subcommand args map
+func (cl *Cli) parseArgs() {
 
-		// If the flag exists then
-		if _, ok := flagList[key]; ok {
-			// Merge names
-			flagList[key].nameu += ", " + nameu
+	cl.SubCommandArgsMap = make(map[string]string)
+	cl.ArgOrder = nil
+	cl.SubCommandPositional = nil
+	for _, tok := range cl.tokenizeArgs(cl.SubCommandArgs) {
+		cl.ArgOrder = append(cl.ArgOrder, tok.name)
+		if tok.flag {
+			cl.SubCommandArgsMap[tok.name] = tok.value
 		} else {
-			// Otherwise add the flag
-			flagList[key] = &flagInfo{
-				nameu:    nameu,
-				name:     f.Name,
-				usage:    f.Usage,
-				defValue: f.DefValue,
-			}
+			cl.SubCommandArgsMap[tok.name] = ""
+			cl.SubCommandPositional = append(cl.SubCommandPositional, tok.name)
 		}
+	}
+}
 
-		// Check and set maximum length for alignment
-		if len(flagList[key].nameu) > maxlen {
-			maxlen = len(flagList[key].nameu)
-		}
-	})
+// UseFlagSetParsing sets the given flag set for parsing the args of the given command
+// The args are parsed by the flag set instead of the map based parser. SubCommandArgsMap
+// contains the values of all the flags of the set and SubCommandArgs contains the remaining args.
+// Parsing errors are returned by Run. A nil flag set restores the map based parser.
+func (cl *Cli) UseFlagSetParsing(command string, fs *flag.FlagSet) {
 
-	var maxlenF = fmt.Sprintf("%d", maxlen)
+	if cl.flagSets == nil {
+		cl.flagSets = make(map[string]*flag.FlagSet)
+	}
 
-	// Fixed flag list
-	flagListF := []string{}
-	for _, v := range flagList {
-		flagline := fmt.Sprintf("%-"+maxlenF+"s : %s", v.nameu, v.usage)
-		if v.defValue != "false" && v.defValue != "" {
-			flagline += " (default \"" + v.defValue + "\")"
-		}
-		flagListF = append(flagListF, flagline)
+	if fs != nil {
+		cl.flagSets[command] = fs
+	} else {
+		delete(cl.flagSets, command)
 	}
-	sort.Strings(flagListF)
+}
+
+// CommandFlags returns the flag set of the given command for defining its own flags
+// The args of the command are parsed by the flag set (see UseFlagSetParsing).
+func (cl *Cli) CommandFlags(command string) *flag.FlagSet {
 
-	// Fixed command list
-	cmdListF := []string{}
-	for cn, cv := range cl.Commands {
-		cmdListF = append(cmdListF, fmt.Sprintf("%-"+maxlenF+"s : %s", cn, cv))
+	if fs, ok := cl.flagSets[command]; ok {
+		return fs
 	}
-	sort.Strings(cmdListF)
 
-	// Header and description
-	usage := "Usage: " + cl.Name + " [OPTIONS] COMMAND [arg...]\n\n"
-	if cl.Description != "" {
-		usage += cl.Description + "\n\n"
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	cl.UseFlagSetParsing(command, fs)
+
+	return fs
+}
+
+// parseFlagSet parses the subcommand args by the given flag set
+func (cl *Cli) parseFlagSet(fs *flag.FlagSet) {
+
+	cl.SubCommandArgsMap = make(map[string]string)
+	cl.ArgOrder = nil
+	cl.SubCommandPositional = nil
+	if err := fs.Parse(cl.SubCommandArgs); err != nil {
+		cl.argsErr = err
+		return
 	}
 
-	// Options
-	if len(flagListF) > 0 {
-		usage += "Options:\n"
-		for _, f := range flagListF {
-			usage += fmt.Sprintf("  %s\n", f)
+	fs.VisitAll(func(f *flag.Flag) {
+		cl.SubCommandArgsMap[f.Name] = f.Value.String()
+	})
+	for _, tok := range cl.tokenizeArgs(cl.SubCommandArgs[:len(cl.SubCommandArgs)-fs.NArg()]) {
+		if tok.flag && fs.Lookup(tok.name) != nil {
+			cl.ArgOrder = append(cl.ArgOrder, tok.name)
+		}
+	}
+	cl.SubCommandArgs = fs.Args()
+	cl.SubCommandPositional = fs.Args()
+}
+
+// flagArg returns the value of the given subcommand arg if it's given as a flag
+// The positional args with the same name are not taken into account.
+func (cl Cli) flagArg(name string) (string, bool) {
+
+	// If the args are parsed by a flag set then the parsed args are flags
+	if _, ok := cl.flagSets[cl.command()]; ok {
+		for _, n := range cl.ArgOrder {
+			if n == name {
+				return cl.SubCommandArgsMap[name], true
+			}
 		}
+		return "", false
 	}
 
-	// Commands
-	if len(cmdListF) > 0 {
-		usage += "\nCommands:\n"
-		for _, c := range cmdListF {
-			usage += fmt.Sprintf("  %s\n", c)
+	var val string
+	var found bool
+	for _, tok := range cl.tokenizeArgs(cl.SubCommandArgs) {
+		if tok.flag && tok.name == name {
+			val, found = tok.value, true
 		}
 	}
 
-	fmt.Println(usage)
+	return val, found
 }
 
-// Table represent tabular data as a table
-type Table struct {
-	data     [][]string
-	colSizes map[int]int
+// argToken represents a parsed subcommand arg
+type argToken struct {
+	name  string
+	value string
+	flag  bool
 }
 
-// Data gets data
-func (t *Table) Data() [][]string {
-	return t.data
+// tokenizeArgs parses the given args into tokens in the given order
+// The args can be given as `--name value`, `--name=value`, `-n value` or `-n=value`
+func (cl Cli) tokenizeArgs(args []string) []argToken {
+
+	var tokens []argToken
+	cur := -1 // the flag that waits for its value
+	for _, v := range args {
+		// If it's an arg then
+		if strings.HasPrefix(v, "-") && (cl.noNegativeNumbers || !isNegativeNumber(v)) {
+			name, val, hasVal := splitArg(v)
+			cur = -1
+			if name == "" {
+				continue
+			}
+
+			// Resolve the alias if any
+			if n, ok := cl.argAliases[name]; ok {
+				name = n
+			}
+
+			// If it's a boolean flag without a value then don't wait for the value
+			if !hasVal && cl.boolFlags[name] {
+				val, hasVal = "true", true
+			}
+
+			tokens = append(tokens, argToken{name: name, value: val, flag: true})
+			if !hasVal {
+				cur = len(tokens) - 1 // wait for the value
+			}
+		} else {
+			// Otherwise set it to current arg or add it as arg
+			if cur >= 0 {
+				tokens[cur].value = v
+				cur = -1
+			} else {
+				tokens = append(tokens, argToken{name: v})
+			}
+		}
+	}
+
+	return tokens
 }
 
-// SetData sets a data by the given row, column and value
-func (t *Table) SetData(row, col int, val string) error {
+// SetBoolFlags sets the given subcommand flags as boolean flags
+// A boolean flag is set to `true` unless it's given with a value (i.e. --force=false)
+// and the following arg is not consumed as its value.
+func (cl *Cli) SetBoolFlags(names ...string) {
 
-	// Check row and column numbers
-	if row < 1 || col < 1 {
-		return errors.New("invalid row or column index")
+	if cl.boolFlags == nil {
+		cl.boolFlags = make(map[string]bool)
 	}
+	for _, name := range names {
+		cl.boolFlags[name] = true
+	}
+}
 
-	// Increase the row capacity if it's necessary
-	if row > len(t.data) {
-		nt := make([][]string, row)
-		copy(nt, t.data)
-		t.data = nt
+// AllowNegativeNumberArgs sets whether the negative numbers (i.e. -5) are treated
+// as values instead of flags in the subcommand args. It's allowed by default.
+func (cl *Cli) AllowNegativeNumberArgs(allow bool) {
+	cl.noNegativeNumbers = !allow
+}
+
+// isNegativeNumber reports whether the given arg is a negative number
+func isNegativeNumber(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' || (arg[1] != '.' && (arg[1] < '0' || arg[1] > '9')) {
+		return false
 	}
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil
+}
 
-	// Increase the column capacity if it's necessary
-	if col > len(t.data[row-1]) {
-		nr := make([]string, col)
-		copy(nr, t.data[row-1])
-		t.data[row-1] = nr
+// splitArg splits the given arg token into its name and value
+// The last return value reports whether the value is given by `=`
+func splitArg(arg string) (string, string, bool) {
+
+	arg = strings.TrimLeft(arg, "-")
+	if i := strings.Index(arg, "="); i >= 0 {
+		return arg[:i], arg[i+1:], true
 	}
 
-	// Set the value
-	t.data[row-1][col-1] = val
+	return arg, "", false
+}
 
-	// Set the column size for alignment
-	if t.colSizes == nil {
-		t.colSizes = make(map[int]int)
+// ArgAlias sets the given alias for the given subcommand arg name (i.e. `o` for `out`)
+func (cl *Cli) ArgAlias(alias, name string) {
+
+	if cl.argAliases == nil {
+		cl.argAliases = make(map[string]string)
 	}
+	cl.argAliases[alias] = name
+}
 
-	if len(val) > t.colSizes[col-1] {
-		t.colSizes[col-1] = len(val)
+// SetFlagNormalizer sets the given function for normalizing the value of the given flag
+// (i.e. trimming, lowercasing) after parsing. An empty name sets it for all the flags.
+// The flag specific normalizer runs after the one for all the flags.
+func (cl *Cli) SetFlagNormalizer(name string, fn func(string) string) {
+
+	if cl.flagNormalizers == nil {
+		cl.flagNormalizers = make(map[string]func(string) string)
 	}
 
-	return nil
+	if fn != nil {
+		cl.flagNormalizers[name] = fn
+	} else {
+		delete(cl.flagNormalizers, name)
+	}
 }
 
-// AddRow adds a row data by the given row number and column values
-func (t *Table) AddRow(row int, cols ...string) error {
+// normalizeFlags normalizes the flag values by the flag normalizers
+func (cl *Cli) normalizeFlags() {
 
-	// Iterate rows and set data
-	for i, v := range cols {
-		if err := t.SetData(row, i+1, v); err != nil {
-			return err
+	if len(cl.flagNormalizers) == 0 {
+		return
+	}
+
+	for name, val := range cl.Flags {
+		v := val
+		if fn, ok := cl.flagNormalizers[""]; ok {
+			v = fn(v)
+		}
+		if fn, ok := cl.flagNormalizers[name]; ok {
+			v = fn(v)
+		}
+
+		// Update the flag value if it's changed
+		if v != val && flag.Set(name, v) == nil {
+			cl.Flags[name] = v
 		}
 	}
-	return nil
 }
 
-// PrintData prints data
-func (t *Table) PrintData() {
+// parseFlags parses the global flags and reports the errors with the usage
+func (cl *Cli) parseFlags() {
 
-	if len(t.data) == 0 {
+	err := cl.parseGlobalFlags()
+	if err == nil {
 		return
 	}
 
-	// Print data
-	var rowVal string
-	var colSize string
-	for _, row := range t.data {
-		rowVal = ""
-		for i, c := range row {
-			colSize = fmt.Sprintf("%d", t.colSizes[i])
-			rowVal += fmt.Sprintf("%-"+colSize+"s\t", c)
+	// If the help is requested then
+	if err == flag.ErrHelp {
+		cl.PrintUsage()
+		cl.exit(0)
+		return
+	}
+
+	cl.usageError(err)
+}
+
+// parseGlobalFlags parses the global flags silently and returns their errors
+// The unknown flags are reported with the closest flag as a suggestion.
+func (cl *Cli) parseGlobalFlags() error {
+
+	// Parse the flags silently
+	usage := flag.CommandLine.Usage
+	flag.CommandLine.Init(os.Args[0], flag.ContinueOnError)
+	flag.CommandLine.SetOutput(ioutil.Discard)
+	flag.CommandLine.Usage = func() {}
+
+	err := flag.CommandLine.Parse(os.Args[1:])
+
+	// Restore the flag set
+	flag.CommandLine.Init(os.Args[0], flag.ExitOnError)
+	flag.CommandLine.SetOutput(nil)
+	flag.CommandLine.Usage = usage
+
+	if err == nil || err == flag.ErrHelp {
+		return err
+	}
+
+	// If the flag is unknown then
+	const undefined = "flag provided but not defined: "
+	if msg := err.Error(); strings.HasPrefix(msg, undefined) {
+		name := strings.TrimLeft(strings.TrimPrefix(msg, undefined), "-")
+		msg = "unknown flag " + flagName(name)
+
+		// Find the closest flag
+		var names []string
+		flag.VisitAll(func(f *flag.Flag) {
+			if strings.Index(f.Name, "test.") != 0 {
+				names = append(names, f.Name)
+			}
+		})
+		if s, ok := suggest(name, names); ok {
+			msg += "; did you mean " + flagName(s) + "?"
 		}
-		fmt.Println(rowVal)
+		return &ParseError{Err: ErrInvalidFlag, Name: name, msg: msg}
+	}
+
+	return &ParseError{Err: ErrInvalidFlag, Name: flagErrorName(err), msg: err.Error()}
+}
+
+// usageError prints the given error and the usage, and exits
+func (cl *Cli) usageError(err error) {
+	fmt.Fprintln(cl.stderr(), err)
+	fmt.Fprintln(cl.stderr())
+	cl.PrintUsage()
+	cl.exit(2)
+}
+
+// exit exits by the given code through the exit function
+func (cl *Cli) exit(code int) {
+	if cl.ExitFunc != nil {
+		cl.ExitFunc(code)
+		return
+	}
+	os.Exit(code)
+}
+
+// stdout returns the writer of the outputs
+func (cl Cli) stdout() io.Writer {
+	if cl.Stdout != nil {
+		return cl.Stdout
 	}
+	return os.Stdout
+}
+
+// stderr returns the writer of the errors
+func (cl Cli) stderr() io.Writer {
+	if cl.Stderr != nil {
+		return cl.Stderr
+	}
+	return os.Stderr
+}
+
+// InvokedName returns the name of the invoked binary (i.e. the base name of a symlink)
+func (cl Cli) InvokedName() string {
+	if len(os.Args) == 0 {
+		return ""
+	}
+	return filepath.Base(os.Args[0])
+}
+
+// MultiCall sets the commands that are selected by the invoked names (i.e. symlinks of the binary)
+// If the binary is invoked by one of the given names then Init selects its command and
+// passes all the args to the command. Otherwise the args are parsed as usual.
+func (cl *Cli) MultiCall(commands map[string]string) {
+
+	cl.multiCall = make(map[string]string)
+	for k, v := range commands {
+		cl.multiCall[k] = v
+	}
+}
+
+// AddCommand adds a command by the given name, args hint (i.e. SRC DST) and description
+func (cl *Cli) AddCommand(name, argsHint, desc string) {
+
+	cl.addCommand(name, desc)
+	cl.SetArgsHint(name, argsHint)
+}
+
+// addCommand adds a command by the given name and description by keeping the registration order
+func (cl *Cli) addCommand(name, desc string) {
+
+	if cl.Commands == nil {
+		cl.Commands = make(map[string]string)
+	}
+	if _, ok := cl.Commands[name]; !ok {
+		cl.commandOrder = append(cl.commandOrder, name)
+	}
+	cl.Commands[name] = desc
+}
+
+// Alias adds the given alias for the given command
+// The alias is resolved to the command by InitArgs and it's listed with the command in the usage
+func (cl *Cli) Alias(alias, command string) {
+
+	if cl.commandAliases == nil {
+		cl.commandAliases = make(map[string]string)
+	}
+	cl.commandAliases[alias] = command
+}
+
+// aliasesOf returns the sorted aliases of the given command
+func (cl Cli) aliasesOf(command string) []string {
+
+	var aliases []string
+	for a, c := range cl.commandAliases {
+		if c == command {
+			aliases = append(aliases, a)
+		}
+	}
+	sort.Strings(aliases)
+
+	return aliases
+}
+
+// SetArgsHint sets the args hint (i.e. SRC DST) of the given command for the usage
+func (cl *Cli) SetArgsHint(command, hint string) {
+
+	if cl.argsHints == nil {
+		cl.argsHints = make(map[string]string)
+	}
+
+	if hint != "" {
+		cl.argsHints[command] = hint
+	} else {
+		delete(cl.argsHints, command)
+	}
+}
+
+// SetVariadic sets the name of the variadic arg (i.e. FILES) of the given command
+// The positional args after the fixed ones of the args hint are captured by the variadic arg (see Variadic)
+// and the args hint is completed by the variadic arg (i.e. SRC FILES...) if it's not there.
+func (cl *Cli) SetVariadic(command, name string) {
+
+	if cl.variadics == nil {
+		cl.variadics = make(map[string]string)
+	}
+	cl.variadics[command] = name
+
+	if hint := cl.argsHints[command]; !strings.Contains(hint, name+"...") {
+		cl.SetArgsHint(command, strings.TrimSpace(hint+" "+name+"..."))
+	}
+}
+
+// Variadic returns the positional args of the subcommand that are captured by its variadic arg
+// It returns nil if the subcommand has no variadic arg or the fixed positional args are missing.
+func (cl Cli) Variadic() []string {
+
+	if _, ok := cl.variadics[cl.command()]; !ok {
+		return nil
+	}
+
+	fixed := cl.fixedArgs(cl.command())
+	if len(cl.SubCommandPositional) <= len(fixed) {
+		return nil
+	}
+
+	return cl.SubCommandPositional[len(fixed):]
+}
+
+// fixedArgs returns the names of the fixed positional args of the given command by its args hint
+func (cl Cli) fixedArgs(command string) []string {
+
+	var names []string
+	for _, name := range strings.Fields(cl.argsHints[command]) {
+		if !strings.Contains(name, "...") {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// PrintVersion prints version information
+func (cl Cli) PrintVersion(extra bool) {
+	var ver string
+
+	if extra == true {
+		ver += fmt.Sprintf("Bin Version : %s\n", strings.TrimPrefix(cl.Version, "v"))
+		if cl.Commit != "" {
+			ver += fmt.Sprintf("Commit      : %s\n", cl.Commit)
+		}
+		if cl.BuildDate != "" {
+			ver += fmt.Sprintf("Build date  : %s\n", cl.BuildDate)
+		}
+		ver += fmt.Sprintf("Go version  : %s\n", runtime.Version())
+		ver += fmt.Sprintf("OS/Arch     : %s/%s", runtime.GOOS, runtime.GOARCH)
+	} else {
+		ver = fmt.Sprintf("%s", strings.TrimPrefix(cl.Version, "v"))
+	}
+
+	fmt.Fprintln(cl.stdout(), ver)
+}
+
+// PrintBanner prints the name and the version inside a box
+// The description is printed inside the box too if the given description flag is true.
+// The name is colored if the output supports colors.
+func (cl Cli) PrintBanner(description bool) {
+
+	title := cl.Name
+	if f, ok := cl.stdout().(*os.File); ok && colorEnabled(f) {
+		title = ColorCyan.paint(title)
+	}
+	if cl.Version != "" {
+		title += " " + strings.TrimPrefix(cl.Version, "v")
+	}
+
+	lines := []string{title}
+	if description && cl.Description != "" {
+		lines = append(lines, strings.Split(cl.Description, "\n")...)
+	}
+
+	fmt.Fprintln(cl.stdout(), lightBorder.box(lines))
+}
+
+// PrintUsage prints usage info
+// Usage format follows common convention for Go apps. If the subcommand has its own flags
+// (see CommandFlags) then they are printed as the command options after the global options.
+func (cl Cli) PrintUsage() {
+	cl.printUsage(false)
+}
+
+// PrintUsageVerbose prints the usage like PrintUsage and lists the own flags of each command
+// (see CommandFlags) indented beneath the command
+func (cl Cli) PrintUsageVerbose() {
+	cl.printUsage(true)
+}
+
+// printUsage prints the usage with the flags of the commands if verbose is true
+func (cl Cli) printUsage(verbose bool) {
+
+	// Header and description
+	usage := "Usage: " + cl.usageLine() + "\n\n"
+	if cl.Description != "" {
+		usage += wrapText(cl.Description, terminalWidth()) + "\n\n"
+	}
+
+	// Sections
+	usage += formatSections(cl.usageSections(verbose))
+
+	fmt.Fprintln(cl.stdout(), usage)
+}
+
+// usageSection represents a section of the usage that contains aligned labels and texts
+type usageSection struct {
+	title string
+	rows  [][2]string
+}
+
+// usageSections returns the sections of the usage
+// If verbose is true then the flags of the commands are listed beneath them.
+func (cl Cli) usageSections(verbose bool) []usageSection {
+
+	// Options
+	// If the subcommand has its own flags then they are separated from the global ones
+	required := cl.requiredFlags[cl.command()]
+	options := []usageSection{flagsSection("Options", flag.CommandLine, required)}
+	if fs, ok := cl.flagSets[cl.command()]; ok && cl.SubCommand != "" {
+		if sec := flagsSection("Command Options", fs, required); len(sec.rows) > 0 {
+			options[0].title = "Global Options"
+			options = append(options, sec)
+		}
+	}
+
+	// Commands
+	// The second level commands are grouped under their parent commands
+	commands := usageSection{title: "Commands"}
+	var parent string
+	for _, cn := range cl.usageCommandNames() {
+		name, indent := cn, ""
+		if i := strings.Index(cn, " "); i > 0 {
+			if cn[:i] != parent {
+				commands.rows = append(commands.rows, [2]string{cn[:i], ""})
+			}
+			parent, name, indent = cn[:i], cn[i+1:], "  "
+		} else {
+			parent = cn
+		}
+		label := indent + strings.Join(append([]string{name}, cl.aliasesOf(cn)...), ", ")
+		for _, syn := range []string{cl.requiredSynopsis(cn), cl.argsSynopsis(cn)} {
+			if syn != "" {
+				label += " " + syn
+			}
+		}
+		commands.rows = append(commands.rows, [2]string{label, cl.Commands[cn]})
+
+		// If it's verbose and the command has its own flags then list them beneath the command
+		if fs, ok := cl.flagSets[cn]; ok && verbose {
+			for _, r := range flagsSection("", fs, cl.requiredFlags[cn]).rows {
+				commands.rows = append(commands.rows, [2]string{indent + "    " + r[0], r[1]})
+			}
+		}
+	}
+
+	// Highlight the names
+	sections := append(options, commands)
+	if cl.colored() {
+		for _, sec := range sections {
+			for i := range sec.rows {
+				sec.rows[i][0] = ColorCyan.paint(sec.rows[i][0])
+			}
+		}
+	}
+
+	return sections
+}
+
+// colored reports whether the colors are enabled and can be printed to Stdout
+func (cl Cli) colored() bool {
+	f, ok := cl.stdout().(*os.File)
+	return cl.Color && ok && colorEnabled(f)
+}
+
+// flagsSection returns the usage section of the flags of the given flag set
+// The given required flags are marked as required.
+func flagsSection(title string, fs *flag.FlagSet, required []string) usageSection {
+
+	sec := usageSection{title: title}
+	for _, v := range usageFlags(fs) {
+		text := v.usage
+		if v.defValue != "false" && v.defValue != "" {
+			text += " (default \"" + v.defValue + "\")"
+		}
+		for _, name := range required {
+			if isUsageName(v.nameu, name) {
+				text += " (required)"
+				break
+			}
+		}
+		sec.rows = append(sec.rows, [2]string{v.nameu, text})
+	}
+
+	return sec
+}
+
+// PrintCommandUsage prints the usage of the given command with its own flags (see CommandFlags)
+// The second level commands of the command are listed if there is any. If the command has
+// required flags or args (see RequireFlags and SetVariadic) then they're marked in the synopsis
+// and the options.
+func (cl Cli) PrintCommandUsage(command string) {
+
+	var sections []usageSection
+	line := cl.Name + " " + command
+	if req := cl.requiredSynopsis(command); req != "" {
+		line += " " + req
+	}
+	if fs, ok := cl.flagSets[command]; ok {
+		if sec := flagsSection("Options", fs, cl.requiredFlags[command]); len(sec.rows) > 0 {
+			sections = append(sections, sec)
+			line += " [OPTIONS]"
+		}
+	}
+	if hint := cl.argsSynopsis(command); hint != "" {
+		line += " " + hint
+	}
+
+	// Second level commands
+	subs := usageSection{title: "Commands"}
+	for _, cn := range cl.usageCommandNames() {
+		if strings.HasPrefix(cn, command+" ") {
+			label := strings.TrimPrefix(cn, command+" ")
+			for _, syn := range []string{cl.requiredSynopsis(cn), cl.argsSynopsis(cn)} {
+				if syn != "" {
+					label += " " + syn
+				}
+			}
+			subs.rows = append(subs.rows, [2]string{label, cl.Commands[cn]})
+		}
+	}
+	if len(subs.rows) > 0 {
+		sections = append(sections, subs)
+		line += " COMMAND"
+	}
+
+	usage := "Usage: " + line + "\n"
+	if desc := cl.Commands[command]; desc != "" {
+		usage += "\n" + wrapText(desc, terminalWidth()) + "\n"
+	}
+	if len(sections) > 0 {
+		usage += "\n" + formatSections(sections)
+	}
+
+	fmt.Fprintln(cl.stdout(), usage)
+}
+
+// IsHelpRequested reports whether the help is requested by the `help` command
+// or the `-h`, `--help` flags
+func (cl Cli) IsHelpRequested() bool {
+
+	if cl.helpRequested {
+		return true
+	}
+	for _, name := range []string{"h", "help"} {
+		if f := flag.Lookup(name); f != nil && f.Value.String() == "true" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatSections returns the given sections
+// The labels of all the sections are aligned by the longest one, titles don't affect the alignment
+func formatSections(sections []usageSection) string {
+
+	// Find the longest label for alignment
+	width := 0
+	for _, sec := range sections {
+		if l := labelWidth(sec.rows); l > width {
+			width = l
+		}
+	}
+
+	// Format the non-empty sections
+	var out string
+	for _, sec := range sections {
+		if len(sec.rows) == 0 {
+			continue
+		}
+		if out != "" {
+			out += "\n"
+		}
+		out += sec.title + ":\n"
+		for _, r := range sec.rows {
+			out += formatPair("  ", r, width)
+		}
+	}
+
+	return out
+}
+
+// PrintKV prints the given key/value pairs to the given writer as aligned `key : value` lines
+// The continuation lines of the multi-line values are indented under the values.
+func (cl Cli) PrintKV(w io.Writer, pairs [][2]string) {
+
+	width := labelWidth(pairs)
+	for _, p := range pairs {
+		fmt.Fprint(w, formatPair("", p, width))
+	}
+}
+
+// DiffRow represents a row of PrintDiff
+type DiffRow struct {
+	Key      string
+	Expected string
+	Actual   string
+}
+
+// PrintDiff prints the given rows as an aligned table of the keys, expected and actual values
+// The mismatched rows are prefixed with a `!` marker and colored if the output supports colors.
+func (cl Cli) PrintDiff(rows []DiffRow) {
+
+	var table Table
+	table.SetHeader("  KEY", "EXPECTED", "ACTUAL")
+	table.SetHeaderSeparator(false)
+	table.SetTabWidth(4)
+	for i, r := range rows {
+		marker := "  "
+		if r.Expected != r.Actual {
+			marker = "! "
+		}
+		table.AddRow(i+1, marker+r.Key, r.Expected, r.Actual)
+	}
+	table.SetColor(true)
+	table.SetRowStyle(func(row []string) (Color, bool) {
+		return ColorRed, strings.HasPrefix(row[0], "!")
+	})
+
+	table.WriteTo(cl.stdout())
+}
+
+// labelWidth returns the width of the longest label of the given pairs
+func labelWidth(pairs [][2]string) int {
+
+	width := 0
+	for _, p := range pairs {
+		if l := visibleLen(p[0]); l > width {
+			width = l
+		}
+	}
+
+	return width
+}
+
+// formatPair returns the given label/text pair as a line that's aligned by the given label width
+func formatPair(indent string, pair [2]string, width int) string {
+	text := strings.Replace(pair[1], "\n", "\n"+indent+strings.Repeat(" ", width+3), -1)
+	return indent + padRight(pair[0], width) + " : " + text + "\n"
+}
+
+// terminalWidth returns the terminal width by the COLUMNS environment variable (defaults to 80)
+func terminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return 80
+}
+
+// wrapText wraps the lines of the given text by the given width
+// Lines that fit into the width are kept as is
+func wrapText(text string, width int) string {
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if visibleLen(line) <= width {
+			continue
+		}
+
+		// Fill the words into lines
+		var wrapped []string
+		var cur string
+		for _, w := range strings.Fields(line) {
+			if cur != "" && visibleLen(cur)+1+visibleLen(w) > width {
+				wrapped = append(wrapped, cur)
+				cur = ""
+			}
+			if cur != "" {
+				cur += " "
+			}
+			cur += w
+		}
+		lines[i] = strings.Join(append(wrapped, cur), "\n")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// usageLine returns the usage line of the cli
+func (cl Cli) usageLine() string {
+	return cl.Name + " [OPTIONS] COMMAND [arg...]"
+}
+
+// commandLabel returns the usage label of the given command including its args hint
+func (cl Cli) commandLabel(command string) string {
+	if hint := cl.argsHints[command]; hint != "" {
+		return command + " " + hint
+	}
+	return command
+}
+
+// requiredSynopsis returns the required flags of the given command surrounded by <> for the usage
+func (cl Cli) requiredSynopsis(command string) string {
+
+	flags := make([]string, len(cl.requiredFlags[command]))
+	for i, name := range cl.requiredFlags[command] {
+		flags[i] = "<" + flagName(name) + ">"
+	}
+
+	return strings.Join(flags, " ")
+}
+
+// argsSynopsis returns the args hint of the given command for the usage
+// If the command has a variadic arg (see SetVariadic) or any required declaration (see RequireFlags)
+// then the fixed args which are required are surrounded by <> and the variadic ones by [].
+// Otherwise the hint is kept as is.
+func (cl Cli) argsSynopsis(command string) string {
+
+	hint := cl.argsHints[command]
+	if _, ok := cl.variadics[command]; !ok && len(cl.requiredFlags[command]) == 0 {
+		return hint
+	}
+
+	args := strings.Fields(hint)
+	for i, a := range args {
+		switch {
+		case strings.HasPrefix(a, "<") || strings.HasPrefix(a, "["):
+			// Already marked
+		case strings.Contains(a, "..."):
+			args[i] = "[" + a + "]"
+		default:
+			args[i] = "<" + a + ">"
+		}
+	}
+
+	return strings.Join(args, " ")
+}
+
+// command returns the name of the runtime subcommand including its second level command if any
+func (cl Cli) command() string {
+	if cl.SubSubCommand != "" {
+		return cl.SubCommand + " " + cl.SubSubCommand
+	}
+	return cl.SubCommand
+}
+
+// isCommand reports whether the given name is a command or the parent of the second level commands
+func (cl Cli) isCommand(name string) bool {
+
+	if _, ok := cl.Commands[name]; ok {
+		return true
+	}
+	for c := range cl.Commands {
+		if strings.HasPrefix(c, name+" ") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// commandNames returns the sorted command names
+func (cl Cli) commandNames() []string {
+	names := make([]string, 0, len(cl.Commands))
+	for c := range cl.Commands {
+		names = append(names, c)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// usageCommandNames returns the command names in the usage order (see KeepUsageOrder)
+// The second level commands follow their parent commands.
+func (cl Cli) usageCommandNames() []string {
+
+	if !cl.KeepUsageOrder {
+		return cl.commandNames()
+	}
+
+	// Order the commands of the literal first and then the registered ones
+	registered := make(map[string]bool)
+	for _, cn := range cl.commandOrder {
+		registered[cn] = true
+	}
+	var names []string
+	for _, cn := range cl.commandNames() {
+		if !registered[cn] {
+			names = append(names, cn)
+		}
+	}
+	for _, cn := range cl.commandOrder {
+		if _, ok := cl.Commands[cn]; ok {
+			names = append(names, cn)
+		}
+	}
+
+	// Group the second level commands by their parents
+	var parents []string
+	children := make(map[string][]string)
+	for _, cn := range names {
+		p := cn
+		if i := strings.Index(cn, " "); i > 0 {
+			p = cn[:i]
+		}
+		if _, ok := children[p]; !ok {
+			parents = append(parents, p)
+			children[p] = nil
+		}
+		if p != cn {
+			children[p] = append(children[p], cn)
+		}
+	}
+	names = names[:0]
+	for _, p := range parents {
+		if _, ok := cl.Commands[p]; ok {
+			names = append(names, p)
+		}
+		names = append(names, children[p]...)
+	}
+
+	return names
+}
+
+// usageFlag represents a flag in the usage
+type usageFlag struct {
+	nameu    string
+	name     string
+	usage    string
+	defValue string
+}
+
+// usageFlags returns the flags of the given flag set
+// Flags are grouped by their usage and sorted by their usage names
+func usageFlags(fs *flag.FlagSet) []*usageFlag {
+
+	// Iterate flags
+	flagMap := make(map[string]*usageFlag)
+	fs.VisitAll(func(f *flag.Flag) {
+
+		// If the flag name starts with `test.` then
+		if strings.Index(f.Name, "test.") == 0 {
+			return
+		}
+
+		// Set key by the flag usage for grouping
+		key := fmt.Sprint(f.Usage)
+
+		// Init usage name
+		nameu := flagName(f.Name)
+
+		// If the flag exists then
+		if _, ok := flagMap[key]; ok {
+			// Merge names
+			flagMap[key].nameu += ", " + nameu
+		} else {
+			// Otherwise add the flag
+			flagMap[key] = &usageFlag{
+				nameu:    nameu,
+				name:     f.Name,
+				usage:    f.Usage,
+				defValue: f.DefValue,
+			}
+		}
+	})
+
+	// Sort flags
+	flagList := make([]*usageFlag, 0, len(flagMap))
+	for _, v := range flagMap {
+		flagList = append(flagList, v)
+	}
+	sort.Sort(usageFlagsByName(flagList))
+
+	return flagList
+}
+
+// usageFlagsByName implements sort.Interface for sorting flags by their usage names
+type usageFlagsByName []*usageFlag
+
+func (u usageFlagsByName) Len() int           { return len(u) }
+func (u usageFlagsByName) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u usageFlagsByName) Less(i, j int) bool { return u[i].nameu < u[j].nameu }
+
+// isUsageName reports whether the given flag name is one of the names of the given usage names (i.e. -r, --region)
+func isUsageName(nameu, name string) bool {
+
+	for _, n := range strings.Split(nameu, ", ") {
+		if n == flagName(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// flagName returns the usage name of the given flag name
+func flagName(name string) string {
+	if len(name) > 2 {
+		return "--" + name
+	}
+	return "-" + name
+}
+
+// Table represent tabular data as a table
+// The zero value is ready to use. The methods that read or write the data (i.e. SetData, AddRow,
+// Data and PrintData) are safe for concurrent use. The settings should be set before sharing the table.
+type Table struct {
+	mu          sync.Mutex
+	data        [][]string
+	header      []string
+	colSizes    map[int]int
+	tabWidth    int
+	rowLimit    int
+	barCols     map[int]int
+	fixedCols   map[int]int
+	maxCols     map[int]int
+	links       map[[2]int]string
+	color       bool
+	statuses    map[string]Color
+	groups      []HeaderGroup
+	indents     map[int]int
+	maxRowGap   int
+	rowStyle    func(row []string) (Color, bool)
+	noHeaderSep bool
+	aligns      map[int]Align
+	caption     string
+	capAlign    Align
+	style       TableStyle
+	footer      []string
+	sep         string
+	padding     int
+}
+
+// Align represents the alignment of a column
+type Align int
+
+// Alignments
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// HeaderGroup represents a header cell that spans the given number of columns
+type HeaderGroup struct {
+	Label string
+	Span  int
+}
+
+// Data gets data
+// The rows are shared with the table so they should not be modified concurrently.
+func (t *Table) Data() [][]string {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.data
+}
+
+// Header gets header
+func (t *Table) Header() []string {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.header
+}
+
+// SetHeader sets the header by the given column values
+func (t *Table) SetHeader(cols ...string) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.header = make([]string, len(cols))
+	copy(t.header, cols)
+
+	// Set the column sizes for alignment
+	t.recomputeColSizes()
+}
+
+// Set sets the header and the data rows of the table at once
+// Ragged rows are padded with empty cells
+func (t *Table) Set(headers []string, rows [][]string) {
+
+	// Find the column count
+	cols := len(headers)
+	for _, row := range rows {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Reset the table
+	t.header = nil
+	t.data = nil
+
+	if len(headers) > 0 {
+		t.header = make([]string, cols)
+		copy(t.header, headers)
+	}
+
+	if len(rows) > 0 {
+		t.data = make([][]string, len(rows))
+		for i, row := range rows {
+			t.data[i] = make([]string, cols)
+			copy(t.data[i], row)
+		}
+	}
+
+	// Set the column sizes for alignment
+	t.recomputeColSizes()
+}
+
+// RecomputeColSizes rebuilds the column sizes from the header and the data rows
+// It's useful after modifying the data directly (i.e. by the slices of Data).
+func (t *Table) RecomputeColSizes() {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.recomputeColSizes()
+}
+
+// recomputeColSizes rebuilds the column sizes without locking the table
+func (t *Table) recomputeColSizes() {
+
+	t.colSizes = make(map[int]int)
+	for _, row := range append([][]string{t.header, t.footer}, t.data...) {
+		for i, v := range row {
+			t.setColSize(i+1, v)
+		}
+	}
+}
+
+// View returns a new table that contains only the given columns in the given order
+// Table settings and the column settings of the given columns are copied and the columns which
+// don't exist are left blank. The header groups and the row style are not copied since they
+// refer to the columns of the table.
+func (t *Table) View(cols ...int) *Table {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// pick returns the given columns of the given row
+	pick := func(row []string) []string {
+		r := make([]string, len(cols))
+		for i, col := range cols {
+			if col >= 1 && col <= len(row) {
+				r[i] = row[col-1]
+			}
+		}
+		return r
+	}
+
+	var header []string
+	if len(t.header) > 0 {
+		header = pick(t.header)
+	}
+	rows := make([][]string, len(t.data))
+	for i, row := range t.data {
+		rows[i] = pick(row)
+	}
+
+	v := &Table{}
+	v.Set(header, rows)
+	if len(t.footer) > 0 {
+		v.SetFooter(pick(t.footer)...)
+	}
+
+	// Copy the settings
+	v.tabWidth = t.tabWidth
+	v.rowLimit = t.rowLimit
+	v.noHeaderSep = t.noHeaderSep
+	v.maxRowGap = t.maxRowGap
+	v.sep = t.sep
+	v.padding = t.padding
+	v.style = t.style
+	v.color = t.color
+	v.caption = t.caption
+	v.capAlign = t.capAlign
+	if len(t.statuses) > 0 {
+		v.statuses = make(map[string]Color, len(t.statuses))
+		for k, c := range t.statuses {
+			v.statuses[k] = c
+		}
+	}
+	for row, level := range t.indents {
+		v.SetRowIndent(row+1, level)
+	}
+	for i, col := range cols {
+		if w, ok := t.barCols[col-1]; ok {
+			v.SetBarColumn(i+1, w)
+		}
+		if w, ok := t.fixedCols[col-1]; ok {
+			v.SetFixedColWidth(i+1, w)
+		}
+		if w, ok := t.maxCols[col-1]; ok {
+			v.SetMaxColWidth(i+1, w)
+		}
+		if a, ok := t.aligns[col-1]; ok {
+			v.SetColAlign(i+1, a)
+		}
+		for cell, url := range t.links {
+			if cell[1] == col-1 {
+				if v.links == nil {
+					v.links = make(map[[2]int]string)
+				}
+				v.links[[2]int{cell[0], i}] = url
+			}
+		}
+	}
+
+	return v
+}
+
+// DefaultMaxRowGap is the default maximum number of the empty rows that SetData can create
+const DefaultMaxRowGap = 10000
+
+// SetMaxRowGap sets the maximum number of the empty rows that SetData and AddRow can create
+// between the last row and the given row. A zero gap restores DefaultMaxRowGap.
+func (t *Table) SetMaxRowGap(n int) error {
+
+	if n < 0 {
+		return errors.New("invalid row gap")
+	}
+	t.maxRowGap = n
+
+	return nil
+}
+
+// SetData sets a data by the given row, column and value
+func (t *Table) SetData(row, col int, val string) error {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.setData(row, col, val)
+}
+
+// GetData returns the data by the given row and column
+// The cells of the short rows that are never set are returned as empty values.
+func (t *Table) GetData(row, col int) (string, error) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Check row and column numbers
+	if row < 1 || col < 1 || row > len(t.data) || col > t.colCount() {
+		return "", errors.New("invalid row or column index")
+	}
+
+	if col > len(t.data[row-1]) {
+		return "", nil
+	}
+
+	return t.data[row-1][col-1], nil
+}
+
+// Clear removes the rows, their settings (i.e. indents and links) and the footer for reusing the table
+// The header and the table settings are kept.
+func (t *Table) Clear() {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.data = nil
+	t.footer = nil
+	t.indents = nil
+	t.links = nil
+	t.recomputeColSizes()
+}
+
+// EachRow calls the given function for each row in order by the row number and a copy of the row
+// It stops at the first error of the function and returns it.
+func (t *Table) EachRow(fn func(row int, cols []string) error) error {
+
+	// Copy the rows for calling the function without locking the table
+	t.mu.Lock()
+	rows := make([][]string, len(t.data))
+	for i, r := range t.data {
+		rows[i] = make([]string, len(r))
+		copy(rows[i], r)
+	}
+	t.mu.Unlock()
+
+	for i, r := range rows {
+		if err := fn(i+1, r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FilterRows removes the rows that don't match the given predicate and recomputes the column sizes
+// The predicate receives a copy of the row. The rows that are added while filtering are kept.
+func (t *Table) FilterRows(pred func(cols []string) bool) {
+
+	// Check the copies of the rows without locking the table
+	var keep []bool
+	t.EachRow(func(row int, cols []string) error {
+		keep = append(keep, pred(cols))
+		return nil
+	})
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var order []int
+	for i := range t.data {
+		if i >= len(keep) || keep[i] {
+			order = append(order, i)
+		}
+	}
+
+	t.reorderRows(order)
+	t.recomputeColSizes()
+}
+
+// setData sets a data by the given row, column and value without locking the table
+func (t *Table) setData(row, col int, val string) error {
+
+	// Check row and column numbers
+	if row < 1 || col < 1 {
+		return errors.New("invalid row or column index")
+	}
+
+	// Check the gap between the rows for preventing huge allocations by mistake
+	gap := t.maxRowGap
+	if gap == 0 {
+		gap = DefaultMaxRowGap
+	}
+	if row-len(t.data)-1 > gap {
+		return fmt.Errorf("row index %d exceeds the maximum gap of %d rows after the last row", row, gap)
+	}
+
+	// Increase the row capacity if it's necessary
+	if row > len(t.data) {
+		nt := make([][]string, row)
+		copy(nt, t.data)
+		t.data = nt
+	}
+
+	// Increase the column capacity if it's necessary
+	if col > len(t.data[row-1]) {
+		nr := make([]string, col)
+		copy(nr, t.data[row-1])
+		t.data[row-1] = nr
+	}
+
+	// Set the value
+	t.data[row-1][col-1] = val
+
+	// Set the column size for alignment
+	t.setColSize(col, val)
+
+	return nil
+}
+
+// setColSize sets the column size by the given column and value if it's necessary
+func (t *Table) setColSize(col int, val string) {
+
+	if t.colSizes == nil {
+		t.colSizes = make(map[int]int)
+	}
+
+	if l := visibleLen(val); l > t.colSizes[col-1] {
+		t.colSizes[col-1] = l
+	}
+}
+
+// AddRow adds a row data by the given row number and column values
+func (t *Table) AddRow(row int, cols ...string) error {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.addRow(row, cols...)
+}
+
+// addRow adds a row data by the given row number and column values without locking the table
+func (t *Table) addRow(row int, cols ...string) error {
+
+	// Iterate rows and set data
+	for i, v := range cols {
+		if err := t.setData(row, i+1, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintData prints data
+func (t *Table) PrintData() {
+	t.WriteTo(os.Stdout)
+}
+
+// WriteTo writes the data to the given writer as it's printed by PrintData
+// It returns the number of the written bytes.
+func (t *Table) WriteTo(w io.Writer) (int64, error) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.data) == 0 && len(t.header) == 0 && len(t.footer) == 0 {
+		return 0, nil
+	}
+
+	cw := &countWriter{w: w}
+	err := t.renderAll(map[Format]io.Writer{FormatText: cw})
+
+	return cw.n, err
+}
+
+// countWriter counts the bytes that are written to the underlying writer
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// PrintRange prints the header, the rows between the given start and end rows (inclusive)
+// and the footer to the given writer. The end is clamped to the last row. The columns are aligned by
+// the whole table so the ranges of the same table are aligned with each other.
+func (t *Table) PrintRange(w io.Writer, start, end int) error {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if start < 1 || end < start {
+		return errors.New("invalid row range")
+	}
+	if end > len(t.data) {
+		end = len(t.data)
+	}
+
+	rows := t.renderRows(w)
+	sizes := t.renderSizes(rows)
+
+	if err := t.printHeader(w, sizes); err != nil {
+		return err
+	}
+	for i := start - 1; i < end; i++ {
+		if _, err := fmt.Fprintln(w, t.formatRow(rows[i], sizes)); err != nil {
+			return err
+		}
+	}
+
+	return t.printFooter(w, sizes)
+}
+
+// printHeader prints the header groups and the header to the given writer by the given column sizes
+func (t *Table) printHeader(w io.Writer, sizes map[int]int) error {
+
+	if len(t.groups) > 0 {
+		if _, err := fmt.Fprintln(w, t.formatGroups(sizes)); err != nil {
+			return err
+		}
+	}
+	if len(t.header) > 0 {
+		if _, err := fmt.Fprintln(w, t.formatRow(t.renderHeader(), sizes)); err != nil {
+			return err
+		}
+	}
+	if len(t.header) > 0 && !t.noHeaderSep {
+		if _, err := fmt.Fprintln(w, t.formatRow(t.rule(len(t.header), sizes), sizes)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printFooter prints the footer under a dashed line to the given writer by the given column sizes
+func (t *Table) printFooter(w io.Writer, sizes map[int]int) error {
+
+	if len(t.footer) == 0 {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(w, "%s\n%s\n", t.formatRow(t.rule(t.colCount(), sizes), sizes), t.formatRow(t.renderLine(t.footer), sizes))
+	return err
+}
+
+// rule returns the dashed cells of the given number of columns by the given column sizes
+func (t *Table) rule(cols int, sizes map[int]int) []string {
+
+	rule := make([]string, cols)
+	for i := range rule {
+		rule[i] = strings.Repeat("-", sizes[i])
+	}
+
+	return rule
+}
+
+// SetFooter sets the footer (i.e. totals) that is printed under the rows after a dashed line
+// The footer is written as the last row by the Markdown format too. It's omitted by the CSV and NDJSON
+// formats since it can't be told apart from the data records there.
+func (t *Table) SetFooter(cols ...string) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.footer = make([]string, len(cols))
+	copy(t.footer, cols)
+
+	// Set the column sizes for alignment
+	t.recomputeColSizes()
+}
+
+// renderRows returns the data rows as they are rendered to the given writer
+// The links and the colors are rendered only if the writer is a terminal.
+func (t *Table) renderRows(w io.Writer) [][]string {
+
+	if cw, ok := w.(*countWriter); ok {
+		w = cw.w
+	}
+	f, ok := w.(*os.File)
+	links := len(t.links) > 0 && ok && isTerminal(f)
+	colored := t.color && ok && colorEnabled(f)
+	statuses := len(t.statuses) > 0 && colored
+	styles := t.rowStyle != nil && colored
+	if len(t.barCols) == 0 && len(t.fixedCols) == 0 && len(t.maxCols) == 0 && len(t.indents) == 0 && !links && !statuses && !styles {
+		return t.data
+	}
+
+	// Find the maximum values of the bar columns
+	maxes := make(map[int]float64)
+	for col := range t.barCols {
+		_, maxes[col], _, _, _ = t.colStats(col+1, true)
+	}
+
+	// Copy the rows and render the values
+	rows := make([][]string, len(t.data))
+	for i, row := range t.data {
+		rows[i] = make([]string, len(row))
+		var style Color
+		if styles {
+			if c, ok := t.rowStyle(row); ok {
+				style = c
+			}
+		}
+		for col, val := range row {
+			if level, ok := t.indents[i]; ok && col == 0 {
+				val = strings.Repeat(" ", level*indentWidth) + val
+			}
+			if width, ok := t.barCols[col]; ok {
+				val = barCell(val, maxes[col], width)
+			}
+			if width, ok := t.fixedCols[col]; ok {
+				val = truncateVisible(val, width)
+			}
+			if width, ok := t.maxCols[col]; ok {
+				val = ellipsis(val, width)
+			}
+			if url, ok := t.links[[2]int{i, col}]; ok && links {
+				val = hyperlink(val, url)
+			}
+			if c, ok := t.statuses[strings.ToLower(strings.TrimSpace(row[col]))]; ok && statuses {
+				val = c.paint(val)
+			} else if style != ColorNone {
+				val = style.paint(val)
+			}
+			rows[i][col] = val
+		}
+	}
+
+	return rows
+}
+
+// renderHeader returns the header as it's rendered
+func (t *Table) renderHeader() []string {
+	return t.renderLine(t.header)
+}
+
+// renderLine returns the given header or footer cells as they are rendered
+func (t *Table) renderLine(cells []string) []string {
+
+	if len(t.fixedCols) == 0 && len(t.maxCols) == 0 {
+		return cells
+	}
+
+	line := make([]string, len(cells))
+	for col, val := range cells {
+		if width, ok := t.fixedCols[col]; ok {
+			val = truncateVisible(val, width)
+		}
+		if width, ok := t.maxCols[col]; ok {
+			val = ellipsis(val, width)
+		}
+		line[col] = val
+	}
+
+	return line
+}
+
+// SetHeaderSeparator enables or disables printing a dashed line under the header
+// The dashed line is printed by default.
+func (t *Table) SetHeaderSeparator(enabled bool) {
+	t.noHeaderSep = !enabled
+}
+
+// SetCaption sets the caption that is printed below the table
+// The caption is wrapped by the table width. An empty caption removes it.
+func (t *Table) SetCaption(caption string) {
+	t.caption = caption
+}
+
+// SetCaptionAlign sets the alignment of the caption (defaults to AlignLeft)
+func (t *Table) SetCaptionAlign(align Align) error {
+
+	if align < AlignLeft || align > AlignCenter {
+		return errors.New("invalid alignment")
+	}
+	t.capAlign = align
+
+	return nil
+}
+
+// formatCaption returns the caption lines by the given column sizes
+func (t *Table) formatCaption(sizes map[int]int) string {
+
+	// Find the table width
+	var width int
+	if n := t.colCount(); n > 0 {
+		width = t.colStops(sizes, n-1)[n-1] + sizes[n-1]
+	}
+
+	return t.alignCaption(width)
+}
+
+// alignCaption returns the caption lines that are wrapped and aligned by the given table width
+func (t *Table) alignCaption(width int) string {
+
+	if width < 1 {
+		return t.caption
+	}
+
+	lines := strings.Split(wrapText(t.caption, width), "\n")
+	for i, l := range lines {
+		switch t.capAlign {
+		case AlignRight:
+			lines[i] = padLeft(l, width)
+		case AlignCenter:
+			lines[i] = strings.TrimRight(padCenter(l, width), " ")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// SetHeaderGroups sets the groups of the header which are printed above the header
+// Each group spans the given number of columns and the adjacent groups with the same
+// label are merged. The columns that are not covered by the groups are left blank.
+func (t *Table) SetHeaderGroups(groups []HeaderGroup) error {
+
+	for _, g := range groups {
+		if g.Span < 1 {
+			return errors.New("invalid header group span")
+		}
+	}
+
+	// Merge the adjacent groups with the same label
+	t.groups = nil
+	for _, g := range groups {
+		if n := len(t.groups); n > 0 && t.groups[n-1].Label == g.Label {
+			t.groups[n-1].Span += g.Span
+			continue
+		}
+		t.groups = append(t.groups, g)
+	}
+
+	return nil
+}
+
+// formatGroups returns the line of the header groups by the given column sizes
+// The columns are widened for the labels that don't fit into their columns.
+func (t *Table) formatGroups(sizes map[int]int) string {
+
+	// Cover the rest of the columns by blank groups
+	groups := t.groups
+	var cols int
+	for _, g := range groups {
+		cols += g.Span
+	}
+	for ; cols < len(t.header); cols++ {
+		groups = append(groups, HeaderGroup{Span: 1})
+	}
+
+	// Widen the last columns of the groups for the long labels
+	start := 0
+	for _, g := range groups {
+		end := start + g.Span - 1
+		stops := t.colStops(sizes, end)
+		if n := visibleLen(g.Label) - (stops[end] + sizes[end] - stops[start]); n > 0 {
+			sizes[end] += n
+		}
+		start = end + 1
+	}
+
+	// Center the labels over their columns
+	var line string
+	stops := t.colStops(sizes, cols)
+	start = 0
+	for i, g := range groups {
+		end := start + g.Span - 1
+		if i > 0 {
+			line += t.separator(stops[start-1] + sizes[start-1])
+		}
+		line += padCenter(g.Label, stops[end]+sizes[end]-stops[start])
+		start = end + 1
+	}
+
+	return line
+}
+
+// colStops returns the start positions of the columns up to the given column count
+// by the given column sizes. Tab separators are assumed to be expanded to 8 columns.
+func (t *Table) colStops(sizes map[int]int, n int) []int {
+
+	stops := make([]int, n+1)
+	for i := 0; i < n; i++ {
+		l := stops[i] + sizes[i]
+		if t.sep == "" && t.tabWidth == 0 {
+			l += t.padding
+			stops[i+1] = l + 8 - l%8
+		} else {
+			stops[i+1] = l + visibleLen(t.separator(l))
+		}
+	}
+
+	return stops
+}
+
+// renderSizes returns the column sizes for rendering the given rows
+func (t *Table) renderSizes(rows [][]string) map[int]int {
+
+	sizes := make(map[int]int)
+	for k, v := range t.colSizes {
+		sizes[k] = v
+	}
+
+	// Widen the columns for the rendered values if it's necessary
+	if len(t.barCols) > 0 || len(t.indents) > 0 {
+		for _, row := range rows {
+			for i, c := range row {
+				if l := visibleLen(c); l > sizes[i] {
+					sizes[i] = l
+				}
+			}
+		}
+	}
+
+	// Cap the column sizes by the maximum widths
+	for col, width := range t.maxCols {
+		if sizes[col] > width {
+			sizes[col] = width
+		}
+	}
+
+	// Set the fixed column sizes
+	for col, width := range t.fixedCols {
+		sizes[col] = width
+	}
+
+	return sizes
+}
+
+// SetColor enables or disables the colors of the table
+// Colors are printed only if the output is a terminal and the NO_COLOR environment variable is not set
+func (t *Table) SetColor(enabled bool) {
+	t.color = enabled
+}
+
+// SetStatusColors sets the colors of the cells by their values (i.e. OK, FAIL)
+// Values are matched case-insensitively
+func (t *Table) SetStatusColors(colors map[string]Color) {
+
+	t.statuses = make(map[string]Color)
+	for k, v := range colors {
+		t.statuses[strings.ToLower(strings.TrimSpace(k))] = v
+	}
+}
+
+// SetRowStyle sets the function that returns the color of the given row
+// The rows are colored if the function returns true and the colors are printed (see SetColor).
+// The status colors take precedence over the row colors. A nil function removes the styling.
+func (t *Table) SetRowStyle(fn func(row []string) (Color, bool)) {
+	t.rowStyle = fn
+}
+
+// SetCellLink sets the given url as the hyperlink of the given cell
+// The links are printed only if the output is a terminal, otherwise the plain text is printed.
+// An empty url removes the link.
+func (t *Table) SetCellLink(row, col int, url string) error {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if row < 1 || col < 1 {
+		return errors.New("invalid row or column index")
+	}
+
+	if t.links == nil {
+		t.links = make(map[[2]int]string)
+	}
+
+	if url != "" {
+		t.links[[2]int{row - 1, col - 1}] = url
+	} else {
+		delete(t.links, [2]int{row - 1, col - 1})
+	}
+
+	return nil
+}
+
+// indentWidth is the width of an indent level of the rows
+const indentWidth = 2
+
+// SetRowIndent sets the indent level of the first column of the given row for hierarchical data
+// A zero level removes the indent.
+func (t *Table) SetRowIndent(row, level int) error {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if row < 1 || level < 0 {
+		return errors.New("invalid row index or level")
+	}
+
+	if t.indents == nil {
+		t.indents = make(map[int]int)
+	}
+
+	if level > 0 {
+		t.indents[row-1] = level
+	} else {
+		delete(t.indents, row-1)
+	}
+
+	return nil
+}
+
+// SetFixedColWidth sets the exact width of the given column
+// Longer values are truncated and shorter ones are padded. A zero width removes the setting.
+func (t *Table) SetFixedColWidth(col, width int) error {
+
+	if col < 1 || width < 0 {
+		return errors.New("invalid column index or width")
+	}
+
+	if t.fixedCols == nil {
+		t.fixedCols = make(map[int]int)
+	}
+
+	if width > 0 {
+		t.fixedCols[col-1] = width
+	} else {
+		delete(t.fixedCols, col-1)
+	}
+
+	return nil
+}
+
+// SetMaxColWidth sets the maximum width of the given column
+// Longer values are truncated with a trailing ellipsis. A zero width removes the setting.
+func (t *Table) SetMaxColWidth(col, width int) error {
+
+	if col < 1 || width < 0 {
+		return errors.New("invalid column index or width")
+	}
+
+	if t.maxCols == nil {
+		t.maxCols = make(map[int]int)
+	}
+
+	if width > 0 {
+		t.maxCols[col-1] = width
+	} else {
+		delete(t.maxCols, col-1)
+	}
+
+	return nil
+}
+
+// SetColAlign sets the alignment of the given column (defaults to AlignLeft)
+// The alignment can be set before the column has any data.
+func (t *Table) SetColAlign(col int, align Align) error {
+
+	if col < 1 || align < AlignLeft || align > AlignCenter {
+		return errors.New("invalid column index or alignment")
+	}
+
+	if t.aligns == nil {
+		t.aligns = make(map[int]Align)
+	}
+	t.aligns[col-1] = align
+
+	return nil
+}
+
+// SetRowLimit sets the maximum number of the data rows for printing
+// The rest of the rows are summarized as a single line. A zero limit prints all the rows.
+func (t *Table) SetRowLimit(n int) error {
+
+	if n < 0 {
+		return errors.New("invalid row limit")
+	}
+	t.rowLimit = n
+
+	return nil
+}
+
+// SetTabWidth sets the tab width for expanding the column separators to spaces
+// A zero width keeps the separators as tabs
+func (t *Table) SetTabWidth(n int) error {
+
+	if n < 0 {
+		return errors.New("invalid tab width")
+	}
+	t.tabWidth = n
+
+	return nil
+}
+
+// formatRow returns the aligned line of the given row by the given column sizes
+func (t *Table) formatRow(row []string, sizes map[int]int) string {
+
+	var rowVal string
+	var rowLen int
+	for i, c := range row {
+		if i > 0 {
+			sep := t.separator(rowLen)
+			rowVal += sep
+			rowLen += visibleLen(sep)
+		}
+
+		// If it's the last cell and it's left aligned then don't pad it for avoiding the trailing spaces
+		// The fixed width columns are always padded for keeping the fixed width records.
+		if _, fixed := t.fixedCols[i]; i == len(row)-1 && t.aligns[i] == AlignLeft && !fixed {
+			rowVal += c
+			continue
+		}
+		rowVal += t.padCell(i, c, sizes[i])
+		rowLen += sizes[i]
+	}
+	return rowVal
+}
+
+// separator returns the column separator including the padding after the given line length
+// The tab separator is expanded to the next tab stop if the tab width is set.
+func (t *Table) separator(pos int) string {
+
+	pad := strings.Repeat(" ", t.padding)
+	pos += t.padding
+
+	switch {
+	case t.sep != "":
+		return pad + t.sep
+	case t.tabWidth > 0:
+		return pad + strings.Repeat(" ", t.tabWidth-pos%t.tabWidth)
+	}
+
+	return pad + "\t"
+}
+
+// SetSeparator sets the column separator (defaults to a tab)
+// An empty separator restores the default one.
+func (t *Table) SetSeparator(sep string) {
+	t.sep = sep
+}
+
+// SetPadding sets the number of the spaces between the aligned columns and the separators
+func (t *Table) SetPadding(n int) error {
+
+	if n < 0 {
+		return errors.New("invalid padding")
+	}
+	t.padding = n
+
+	return nil
+}
+
+// padCell pads the given value of the given column to the given width by the column alignment
+func (t *Table) padCell(col int, val string, width int) string {
+
+	switch t.aligns[col] {
+	case AlignRight:
+		return padLeft(val, width)
+	case AlignCenter:
+		return padCenter(val, width)
+	}
+
+	return padRight(val, width)
 }
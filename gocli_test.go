@@ -7,8 +7,17 @@
 package gocli_test
 
 import (
+	"bytes"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
 	"os"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/yieldbot/gocli"
@@ -29,10 +38,40 @@ func init() {
 	flag.BoolVar(&versionFlag, "v", false, "Display version information")
 }
 
+// captureStdout returns the standard output of the given function
+func captureStdout(fn func()) string {
+	stdout := os.Stdout
+	defer func() { os.Stdout = stdout }()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		panic(err)
+	}
+	os.Stdout = w
+
+	out := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		out <- buf.String()
+	}()
+
+	fn()
+	w.Close()
+
+	return <-out
+}
+
+// setArgs sets the command line args and parses the global flags by them
+func setArgs(args ...string) {
+	os.Args = append(os.Args[:1:1], args...)
+	flag.CommandLine.Parse(args)
+}
+
 func TestInit_1(t *testing.T) {
 
 	// Reset the args
-	os.Args = os.Args[:2]
+	setArgs()
 
 	// Init cli
 	var cli = gocli.Cli{
@@ -77,8 +116,7 @@ func TestInit_1(t *testing.T) {
 func TestInit_2(t *testing.T) {
 
 	// Reset the args
-	os.Args = os.Args[:2]
-	os.Args = append(os.Args, "cmd", "arg1")
+	setArgs("cmd", "arg1")
 
 	// Init cli
 	var cli = gocli.Cli{
@@ -104,11 +142,42 @@ func TestInit_2(t *testing.T) {
 	}
 }
 
+func TestInitE(t *testing.T) {
+
+	// Reset the args
+	setArgs("cmd", "arg1")
+
+	// Init cli
+	var buf bytes.Buffer
+	var cli = gocli.Cli{
+		Commands: map[string]string{
+			"cmd":  "Test command",
+			"exec": "Exec command",
+		},
+		Stdout: &buf,
+	}
+	cli.CommandFlags("exec").Bool("dry", false, "Dry run")
+	if err := cli.InitE(); err != nil || cli.SubCommand != "cmd" {
+		t.Errorf("invalid init: %q %v", cli.SubCommand, err)
+	}
+
+	setArgs("cdm")
+	err := cli.InitE()
+	if perr, ok := err.(*gocli.ParseError); !ok || perr.Err != gocli.ErrUnknownCommand || perr.Name != "cdm" {
+		t.Errorf("invalid unknown command error: %v", err)
+	}
+
+	setArgs("exec", "-wet")
+	err = cli.InitE()
+	if perr, ok := err.(*gocli.ParseError); !ok || perr.Err != gocli.ErrInvalidFlag || perr.Name != "wet" {
+		t.Errorf("invalid flag error: %v", err)
+	}
+}
+
 func TestInit_3(t *testing.T) {
 
 	// Reset the args
-	os.Args = os.Args[:2]
-	os.Args = append(os.Args, "cmd", "--arg2", "arg3", "arg4")
+	setArgs("cmd", "--arg2", "arg3", "arg4")
 
 	// Init cli
 	var cli = gocli.Cli{
@@ -142,103 +211,1572 @@ func TestInit_3(t *testing.T) {
 	}
 }
 
-func ExampleF_PrintVersion() {
+func TestInit_4(t *testing.T) {
+
+	// Reset the args
+	setArgs("status", "status", "cmd")
+
+	// Init cli
 	var cli = gocli.Cli{
-		Version: "1.0.0",
+		Name: "test",
+		Commands: map[string]string{
+			"status": "Test status command",
+			"cmd":    "Test command",
+		},
 	}
 	cli.Init()
 
-	cli.PrintVersion(false)
-	// Output: 1.0.0
+	if cli.SubCommand != "status" {
+		t.Error("invalid SubCommand")
+	}
+
+	if len(cli.SubCommandArgs) != 2 {
+		t.Fatal("invalid SubCommandArgs")
+	}
+
+	if cli.SubCommandArgs[0] != "status" || cli.SubCommandArgs[1] != "cmd" {
+		t.Error("invalid SubCommandArgs arg")
+	}
 }
 
-func ExampleF_PrintVersionExtra() {
+func TestInit_5(t *testing.T) {
+
+	// Reset the args
+	setArgs("cmd", "--out", "a", "--in=b", "-o=c", "-x", "d", "-f", "--=e", "pos")
+
+	// Init cli
 	var cli = gocli.Cli{
-		Version: "1.0.0",
+		Name: "test",
+		Commands: map[string]string{
+			"cmd": "Test command",
+		},
 	}
+	cli.ArgAlias("o", "out")
 	cli.Init()
 
-	cli.PrintVersion(true)
+	var args = map[string]string{
+		"out": "c",
+		"in":  "b",
+		"x":   "d",
+		"f":   "",
+		"pos": "",
+	}
+	if len(cli.SubCommandArgsMap) != len(args) {
+		t.Errorf("invalid SubCommandArgsMap: %v", cli.SubCommandArgsMap)
+	}
+	for k, v := range args {
+		if cli.SubCommandArgsMap[k] != v {
+			t.Errorf("invalid SubCommandArgsMap arg %s", k)
+		}
+	}
 }
 
-func ExampleF_PrintUsage() {
+func TestDebug(t *testing.T) {
+
+	var buf bytes.Buffer
+	var cli = gocli.Cli{
+		Commands: map[string]string{
+			"cmd": "Command",
+		},
+		LogErr: log.New(&buf, "", 0),
+	}
+
+	cli.InitArgs([]string{"cmd", "foo"})
+	if cli.Debug || buf.Len() > 0 {
+		t.Error("invalid debug mode")
+	}
+
+	cli.InitArgs([]string{"cmd", "debug"})
+	if cli.Debug || buf.Len() > 0 {
+		t.Error("invalid debug mode")
+	}
+
+	cli.InitArgs([]string{"cmd", "--debug"})
+	if !cli.Debug || !strings.HasPrefix(buf.String(), "debug: parsed args") {
+		t.Errorf("invalid debug output: %q", buf.String())
+	}
+
+	cli.InitArgs([]string{"cmd"})
+	if cli.Debug {
+		t.Error("invalid debug mode of the next invocation")
+	}
+
+	cli.Debug = true
+	cli.InitArgs([]string{"cmd"})
+	if !cli.Debug {
+		t.Error("invalid debug mode")
+	}
+}
+
+func TestArgOrder(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Commands: map[string]string{
+			"cmd": "Test command",
+		},
+	}
+	cli.InitArgs([]string{"cmd", "--b=1", "pos", "--a", "2", "--b=3"})
+
+	if strings.Join(cli.ArgOrder, ",") != "b,pos,a,b" {
+		t.Errorf("invalid ArgOrder: %v", cli.ArgOrder)
+	}
+	if cli.SubCommandArgsMap["b"] != "3" {
+		t.Error("invalid SubCommandArgsMap arg b")
+	}
+
+	cli.CommandFlags("cmd").String("b", "", "B")
+	cli.CommandFlags("cmd").String("a", "", "A")
+	cli.InitArgs([]string{"cmd", "-b=1", "-a", "2", "pos"})
+	if strings.Join(cli.ArgOrder, ",") != "b,a" {
+		t.Errorf("invalid ArgOrder: %v", cli.ArgOrder)
+	}
+}
+
+func TestSubCommandPositional(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Commands: map[string]string{
+			"copy": "Copy files",
+		},
+	}
+	cli.InitArgs([]string{"copy", "-r", "src", "--mode=644", "dst"})
+
+	if strings.Join(cli.SubCommandPositional, ",") != "dst" {
+		t.Errorf("invalid SubCommandPositional: %v", cli.SubCommandPositional)
+	}
+
+	cli.InitArgs([]string{"copy", "--mode=644", "src", "dst"})
+	if strings.Join(cli.SubCommandPositional, ",") != "src,dst" {
+		t.Errorf("invalid SubCommandPositional: %v", cli.SubCommandPositional)
+	}
+}
+
+func TestSetBoolFlags(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Commands: map[string]string{
+			"remove": "Remove files",
+		},
+	}
+	cli.SetBoolFlags("force", "verbose")
+	cli.ArgAlias("f", "force")
+	cli.InitArgs([]string{"remove", "-f", "a.txt", "--verbose=false", "--mode", "x", "b.txt"})
+
+	var args = map[string]string{
+		"force":   "true",
+		"verbose": "false",
+		"mode":    "x",
+	}
+	for k, v := range args {
+		if cli.SubCommandArgsMap[k] != v {
+			t.Errorf("invalid SubCommandArgsMap arg %s", k)
+		}
+	}
+	if strings.Join(cli.SubCommandPositional, ",") != "a.txt,b.txt" {
+		t.Errorf("invalid SubCommandPositional: %v", cli.SubCommandPositional)
+	}
+}
+
+func TestInitArgs_globalFlags(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Commands: map[string]string{
+			"cmd": "Test command",
+		},
+	}
+	cli.InitArgs([]string{"--arg", "cmd", "-h", "cmd", "x"})
+
+	if cli.SubCommand != "cmd" {
+		t.Error("invalid SubCommand")
+	}
+	if len(cli.SubCommandArgs) != 1 || cli.SubCommandArgs[0] != "x" {
+		t.Errorf("invalid SubCommandArgs: %v", cli.SubCommandArgs)
+	}
+}
+
+func TestInitArgs_firstPositional(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Commands: map[string]string{
+			"status": "Show status",
+		},
+	}
+
+	cli.InitArgs([]string{"--arg", "c.json", "status", "x"})
+	if cli.SubCommand != "status" || len(cli.SubCommandArgs) != 1 {
+		t.Errorf("invalid command: %s %v", cli.SubCommand, cli.SubCommandArgs)
+	}
+
+	cli.InitArgs([]string{"foo", "status"})
+	if cli.SubCommand != "" {
+		t.Error("invalid SubCommand")
+	}
+	if err := cli.Run(); err == nil || err.Error() != `unknown command "foo"` {
+		t.Errorf("invalid Run error: %v", err)
+	}
+}
+
+func TestInitArgs_equals(t *testing.T) {
 
-	// Init cli
 	var cli = gocli.Cli{
-		Name:        "test",
-		Version:     "1.0.0",
-		Description: "test desc",
 		Commands: map[string]string{
 			"cmd": "Test command",
 		},
 	}
+	cli.InitArgs([]string{"cmd", "--name=foo", "-x=1", "--query=a=b", "--empty=", "-v", "--=bar"})
+
+	var args = map[string]string{
+		"name":  "foo",
+		"x":     "1",
+		"query": "a=b",
+		"empty": "",
+		"v":     "",
+	}
+	if len(cli.SubCommandArgsMap) != len(args) {
+		t.Errorf("invalid SubCommandArgsMap: %v", cli.SubCommandArgsMap)
+	}
+	for k, v := range args {
+		if cli.SubCommandArgsMap[k] != v {
+			t.Errorf("invalid SubCommandArgsMap arg %s", k)
+		}
+	}
+}
+
+func TestInvokedName(t *testing.T) {
+
+	args := os.Args
+	defer func() { os.Args = args }()
+
+	var cli = gocli.Cli{
+		Name: "test",
+	}
+
+	os.Args = []string{"/usr/local/bin/status", "arg"}
+	if cli.InvokedName() != "status" {
+		t.Error("invalid invoked name")
+	}
+
+	os.Args = nil
+	if cli.InvokedName() != "" {
+		t.Error("invalid invoked name")
+	}
+}
+
+func TestMultiCall(t *testing.T) {
+
+	args := os.Args
+	defer func() { os.Args = args }()
+
+	var cli = gocli.Cli{
+		Commands: map[string]string{
+			"ls": "List files",
+			"cp": "Copy files",
+		},
+	}
+	cli.MultiCall(map[string]string{"gocli-ls": "ls"})
+
+	os.Args = []string{"/usr/local/bin/gocli-ls"}
+	setArgs("cp", "-l")
 	cli.Init()
+	if cli.SubCommand != "ls" || len(cli.SubCommandArgs) != 2 || cli.SubCommandArgs[0] != "cp" {
+		t.Errorf("invalid multi-call command: %s %v", cli.SubCommand, cli.SubCommandArgs)
+	}
 
-	cli.PrintUsage()
-	// Output:
-	// Usage: test [OPTIONS] COMMAND [arg...]
-	//
-	// test desc
-	//
-	// Options:
-	//   --arg         : Arg flag (default "test")
-	//   -h, --help    : Display usage
-	//   -v, --version : Display version information
-	//
-	// Commands:
-	//   cmd           : Test command
+	os.Args = []string{"/usr/local/bin/gocli"}
+	setArgs("cp", "a")
+	cli.Init()
+	if cli.SubCommand != "cp" || len(cli.SubCommandArgs) != 1 {
+		t.Errorf("invalid command: %s %v", cli.SubCommand, cli.SubCommandArgs)
+	}
 }
 
-func TestSetData(t *testing.T) {
-	// Create table
-	var table = gocli.Table{}
-	table.SetData(1, 1, "FOO")
-	table.SetData(1, 2, "BAR")
-	table.SetData(2, 1, "1")
-	table.SetData(2, 2, "2")
+func TestAllowNegativeNumberArgs(t *testing.T) {
 
-	var tdata = table.Data()
-	if tdata[0][0] != "FOO" {
-		t.Error("invalid table data")
+	// Init cli
+	var cli = gocli.Cli{
+		Commands: map[string]string{
+			"compute": "Test command",
+		},
 	}
-	if tdata[0][1] != "BAR" {
-		t.Error("invalid table data")
+	cli.InitArgs([]string{"compute", "-5", "--offset", "-1.5", "-x", "-inf"})
+
+	if _, ok := cli.SubCommandArgsMap["-5"]; !ok {
+		t.Error("invalid negative number arg")
 	}
-	if tdata[1][0] != "1" {
-		t.Error("invalid table data")
+	if cli.SubCommandArgsMap["offset"] != "-1.5" {
+		t.Error("invalid negative number value")
 	}
-	if tdata[1][1] != "2" {
-		t.Error("invalid table data")
+	if _, ok := cli.SubCommandArgsMap["inf"]; !ok {
+		t.Error("invalid flag arg")
+	}
+
+	cli.AllowNegativeNumberArgs(false)
+	cli.InitArgs([]string{"compute", "-5"})
+	if _, ok := cli.SubCommandArgsMap["5"]; !ok {
+		t.Error("invalid numeric flag arg")
 	}
 }
 
-func TestAddRow(t *testing.T) {
-	// Create table
-	var table = gocli.Table{}
-	table.AddRow(1, "FOO", "BAR")
-	table.AddRow(2, "1", "2")
+func TestUseFlagSetParsing(t *testing.T) {
 
-	var tdata = table.Data()
-	if tdata[0][0] != "FOO" {
-		t.Error("invalid table data")
+	var cli = gocli.Cli{}
+	var count int
+	cli.Handle("cmd", "Command", func(c *gocli.Cli) error {
+		return nil
+	})
+
+	fs := flag.NewFlagSet("cmd", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.IntVar(&count, "count", 1, "Count")
+	fs.Bool("force", false, "Force")
+	cli.UseFlagSetParsing("cmd", fs)
+
+	cli.InitArgs([]string{"cmd", "-count", "3", "foo", "bar"})
+	if count != 3 || cli.SubCommandArgsMap["count"] != "3" || cli.SubCommandArgsMap["force"] != "false" {
+		t.Errorf("invalid SubCommandArgsMap: %v", cli.SubCommandArgsMap)
 	}
-	if tdata[0][1] != "BAR" {
-		t.Error("invalid table data")
+	if len(cli.SubCommandArgs) != 2 || cli.SubCommandArgs[0] != "foo" {
+		t.Errorf("invalid SubCommandArgs: %v", cli.SubCommandArgs)
 	}
-	if tdata[1][0] != "1" {
-		t.Error("invalid table data")
+	if err := cli.Run(); err != nil {
+		t.Error(err)
 	}
-	if tdata[1][1] != "2" {
-		t.Error("invalid table data")
+
+	cli.InitArgs([]string{"cmd", "-bogus"})
+	if err := cli.Run(); err == nil {
+		t.Error("invalid args error")
 	}
 }
 
-func ExampleF_PrintData() {
-	// Create table
-	var table = gocli.Table{}
-	table.AddRow(1, "FOO", "BAR")
+func TestCommandFlags(t *testing.T) {
 
-	table.PrintData()
-	// Output:
-	// FOO	BAR
+	var cli = gocli.Cli{
+		Commands: map[string]string{
+			"create": "Create",
+			"delete": "Delete",
+		},
+	}
+	cli.CommandFlags("create").String("name", "", "Name of the new item")
+	cli.CommandFlags("delete").Bool("name", false, "Delete by name")
+
+	if cli.CommandFlags("create").Lookup("name") == nil {
+		t.Error("invalid command flag set")
+	}
+
+	cli.InitArgs([]string{"create", "-name", "foo", "bar"})
+	if cli.SubCommandArgsMap["name"] != "foo" || len(cli.SubCommandArgs) != 1 {
+		t.Errorf("invalid create args: %v %v", cli.SubCommandArgsMap, cli.SubCommandArgs)
+	}
+
+	cli.InitArgs([]string{"delete", "-name", "bar"})
+	if cli.SubCommandArgsMap["name"] != "true" || len(cli.SubCommandArgs) != 1 {
+		t.Errorf("invalid delete args: %v %v", cli.SubCommandArgsMap, cli.SubCommandArgs)
+	}
+}
+
+func TestPrintUsage_commandOptions(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Name: "test",
+		Commands: map[string]string{
+			"create": "Create",
+		},
+	}
+	cli.CommandFlags("create").String("name", "", "Name of the new item")
+
+	out := captureStdout(cli.PrintUsage)
+	if !strings.Contains(out, "\nOptions:\n") || strings.Contains(out, "Command Options") {
+		t.Errorf("invalid usage output: %q", out)
+	}
+
+	cli.InitArgs([]string{"create"})
+	out = captureStdout(cli.PrintUsage)
+	if !strings.Contains(out, "\nGlobal Options:\n  --arg") || !strings.Contains(out, "\nCommand Options:\n  --name        : Name of the new item\n") {
+		t.Errorf("invalid usage output: %q", out)
+	}
+}
+
+func TestSetFlagNormalizer(t *testing.T) {
+
+	// Reset the args
+	setArgs()
+	defer flag.Set("arg", "test")
+	flag.Set("arg", "  Foo ")
+
+	// Init cli
+	var cli = gocli.Cli{}
+	cli.SetFlagNormalizer("", strings.TrimSpace)
+	cli.SetFlagNormalizer("arg", strings.ToLower)
+	cli.SetFlagNormalizer("h", func(v string) string {
+		return "invalid"
+	})
+	cli.Init()
+
+	if cli.Flags["arg"] != "foo" || argFlag != "foo" {
+		t.Error("invalid normalized flag")
+	}
+
+	if cli.Flags["h"] != "false" || usageFlag {
+		t.Error("invalid normalized flag")
+	}
+}
+
+func ExampleCli_PrintVersion() {
+	var cli = gocli.Cli{
+		Version: "1.0.0",
+	}
+	cli.Init()
+
+	cli.PrintVersion(false)
+	// Output: 1.0.0
+}
+
+func ExampleCli_PrintVersion_extra() {
+	var cli = gocli.Cli{
+		Version: "1.0.0",
+	}
+	cli.Init()
+
+	cli.PrintVersion(true)
+}
+
+func TestPrintVersion_build(t *testing.T) {
+
+	var buf bytes.Buffer
+	var cli = gocli.Cli{
+		Version:   "1.0.0",
+		Commit:    "abc123",
+		BuildDate: "2016-01-02",
+		Stdout:    &buf,
+	}
+
+	cli.PrintVersion(true)
+	out := buf.String()
+	if !strings.HasPrefix(out, "Bin Version : 1.0.0\nCommit      : abc123\nBuild date  : 2016-01-02\nGo version  : ") {
+		t.Errorf("invalid version output: %q", out)
+	}
+	if !strings.Contains(out, "OS/Arch     : "+runtime.GOOS+"/"+runtime.GOARCH+"\n") {
+		t.Errorf("invalid version output: %q", out)
+	}
+
+	buf.Reset()
+	cli.Commit = ""
+	cli.PrintVersion(true)
+	if strings.Contains(buf.String(), "Commit") {
+		t.Errorf("invalid version output: %q", buf.String())
+	}
+}
+
+func ExampleCli_PrintBanner() {
+	var cli = gocli.Cli{
+		Name:        "test",
+		Version:     "v1.0.0",
+		Description: "Test app",
+	}
+
+	cli.PrintBanner(true)
+	// Output:
+	// ┌────────────┐
+	// │ test 1.0.0 │
+	// │ Test app   │
+	// └────────────┘
+}
+
+func ExampleCli_PrintKV() {
+	var cli = gocli.Cli{}
+
+	cli.PrintKV(os.Stdout, [][2]string{
+		{"Name", "test"},
+		{"Status", "running"},
+		{"Hosts", "foo\nbar"},
+	})
+	// Output:
+	// Name   : test
+	// Status : running
+	// Hosts  : foo
+	//          bar
+}
+
+func TestPrintDiff(t *testing.T) {
+
+	var buf bytes.Buffer
+	var cli = gocli.Cli{
+		Stdout: &buf,
+	}
+
+	defer gocli.SetTerminal(true)()
+	cli.PrintDiff([]gocli.DiffRow{
+		{"port", "80", "80"},
+		{"host", "example.com", "localhost"},
+	})
+	if buf.String() != "  KEY   EXPECTED    ACTUAL\n  port  80          80\n! host  example.com localhost\n" {
+		t.Errorf("invalid diff output: %q", buf.String())
+	}
+}
+
+func ExampleCli_PrintUsage() {
+
+	// Init cli
+	var cli = gocli.Cli{
+		Name:        "test",
+		Version:     "1.0.0",
+		Description: "test desc",
+		Commands: map[string]string{
+			"cmd": "Test command",
+		},
+	}
+	cli.Init()
+
+	cli.PrintUsage()
+	// Output:
+	// Usage: test [OPTIONS] COMMAND [arg...]
+	//
+	// test desc
+	//
+	// Options:
+	//   --arg         : Arg flag (default "test")
+	//   -h, --help    : Display usage
+	//   -v, --version : Display version information
+	//
+	// Commands:
+	//   cmd           : Test command
+}
+
+func TestSetData(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetData(1, 1, "FOO")
+	table.SetData(1, 2, "BAR")
+	table.SetData(2, 1, "1")
+	table.SetData(2, 2, "2")
+
+	var tdata = table.Data()
+	if tdata[0][0] != "FOO" {
+		t.Error("invalid table data")
+	}
+	if tdata[0][1] != "BAR" {
+		t.Error("invalid table data")
+	}
+	if tdata[1][0] != "1" {
+		t.Error("invalid table data")
+	}
+	if tdata[1][1] != "2" {
+		t.Error("invalid table data")
+	}
+}
+
+func TestSetMaxRowGap(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+
+	if err := table.AddRow(1000000, "foo"); err == nil {
+		t.Error("invalid row gap error")
+	}
+	if len(table.Data()) != 0 {
+		t.Error("invalid table data")
+	}
+
+	if err := table.SetMaxRowGap(-1); err == nil {
+		t.Error("invalid row gap error")
+	}
+	table.SetMaxRowGap(1)
+	if err := table.AddRow(2, "foo"); err != nil {
+		t.Error(err)
+	}
+	if err := table.AddRow(5, "bar"); err == nil {
+		t.Error("invalid row gap error")
+	}
+}
+
+func TestAddRow(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "FOO", "BAR")
+	table.AddRow(2, "1", "2")
+
+	var tdata = table.Data()
+	if tdata[0][0] != "FOO" {
+		t.Error("invalid table data")
+	}
+	if tdata[0][1] != "BAR" {
+		t.Error("invalid table data")
+	}
+	if tdata[1][0] != "1" {
+		t.Error("invalid table data")
+	}
+	if tdata[1][1] != "2" {
+		t.Error("invalid table data")
+	}
+}
+
+func ExampleTable_PrintData() {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "FOO", "BAR")
+
+	table.PrintData()
+	// Output:
+	// FOO	BAR
+}
+
+func TestWriteTo(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "foo", "bar")
+
+	var buf bytes.Buffer
+	n, err := table.WriteTo(&buf)
+	if err != nil || n != int64(buf.Len()) || buf.String() != "foo\tbar\n" {
+		t.Errorf("invalid table output: %q", buf.String())
+	}
+
+	// Cli writers
+	buf.Reset()
+	var cli = gocli.Cli{
+		Version: "1.0.0",
+		Stdout:  &buf,
+	}
+	cli.PrintVersion(false)
+	if buf.String() != "1.0.0\n" {
+		t.Errorf("invalid version output: %q", buf.String())
+	}
+}
+
+func TestSetHeader(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "STATUS")
+	table.AddRow(1, "foo", "up")
+
+	var theader = table.Header()
+	if len(theader) != 2 {
+		t.Error("invalid table header")
+	}
+	if theader[0] != "NAME" {
+		t.Error("invalid table header")
+	}
+	if theader[1] != "STATUS" {
+		t.Error("invalid table header")
+	}
+	if len(table.Data()) != 1 {
+		t.Error("invalid table data")
+	}
+}
+
+func TestSetHeaderSeparator(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "SIZE")
+	table.AddRow(1, "foobar", "1")
+	table.SetTabWidth(1)
+
+	out := captureStdout(table.PrintData)
+	if out != "NAME   SIZE\n------ ----\nfoobar 1\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+
+	table.SetHeaderSeparator(false)
+	out = captureStdout(table.PrintData)
+	if out != "NAME   SIZE\nfoobar 1\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestSetColAlign(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+
+	if err := table.SetColAlign(0, gocli.AlignRight); err == nil {
+		t.Error("invalid column index error")
+	}
+
+	table.SetColAlign(2, gocli.AlignRight)
+	table.SetColAlign(3, gocli.AlignCenter)
+	table.AddRow(1, "foo", "1", "a")
+	table.AddRow(2, "bar", "1234", "abcde")
+	table.SetTabWidth(1)
+
+	out := captureStdout(table.PrintData)
+	if out != "foo    1   a  \nbar 1234 abcde\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestTable_concurrent(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func(row int) {
+			defer wg.Done()
+			table.AddRow(row, fmt.Sprintf("row%d", row), "x")
+			table.WriteTo(ioutil.Discard)
+			table.PrintRange(ioutil.Discard, 1, row)
+			table.SetData(row, 3, "y")
+		}(i)
+	}
+	wg.Wait()
+
+	// Set the table while reading it
+	var other = gocli.Table{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		other.Set([]string{"NAME"}, [][]string{{"foo"}, {"bar"}})
+		other.SetFooter("Total")
+	}()
+	go func() {
+		defer wg.Done()
+		other.PrintRange(ioutil.Discard, 1, 2)
+		other.AddTotalsRow("Total")
+	}()
+	wg.Wait()
+
+	tdata := table.Data()
+	if len(tdata) != 10 {
+		t.Fatal("invalid table data")
+	}
+	for i, row := range tdata {
+		if len(row) != 3 || row[0] != fmt.Sprintf("row%d", i+1) || row[2] != "y" {
+			t.Errorf("invalid table row: %q", row)
+		}
+	}
+}
+
+func TestGetData(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "foo", "1")
+	table.AddRow(2, "bar")
+
+	if v, err := table.GetData(1, 2); err != nil || v != "1" {
+		t.Errorf("invalid data: %q %v", v, err)
+	}
+	if v, err := table.GetData(2, 2); err != nil || v != "" {
+		t.Errorf("invalid data: %q %v", v, err)
+	}
+	for _, rc := range [][2]int{{0, 1}, {1, 0}, {3, 1}, {1, 3}} {
+		if _, err := table.GetData(rc[0], rc[1]); err == nil {
+			t.Errorf("invalid row or column index error: %v", rc)
+		}
+	}
+}
+
+func TestEachRow(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "foo", "1")
+	table.AddRow(2, "bar", "2")
+	table.AddRow(3, "baz", "3")
+
+	var rows []int
+	err := table.EachRow(func(row int, cols []string) error {
+		rows = append(rows, row)
+		cols[0] = "changed"
+		if cols[1] == "2" {
+			return errors.New("stop")
+		}
+		return nil
+	})
+	if err == nil || err.Error() != "stop" || len(rows) != 2 || rows[0] != 1 || rows[1] != 2 {
+		t.Errorf("invalid rows: %v %v", rows, err)
+	}
+	if v, _ := table.GetData(1, 1); v != "foo" {
+		t.Errorf("invalid table data: %q", v)
+	}
+}
+
+func TestFilterRows(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "foobarbaz", "1")
+	table.AddRow(2, "foo", "2")
+	table.AddRow(3, "bar", "3")
+	table.SetRowIndent(3, 1)
+	table.SetTabWidth(1)
+
+	table.FilterRows(func(cols []string) bool {
+		v, _ := table.GetData(1, 2)
+		return cols[1] != v
+	})
+	out := captureStdout(table.PrintData)
+	if out != "foo   2\n  bar 3\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestClear(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "SIZE")
+	table.AddRow(1, "foobarbaz", "1")
+	table.SetTabWidth(1)
+
+	table.Clear()
+	if len(table.Data()) != 0 {
+		t.Error("invalid table data")
+	}
+
+	table.AddRow(1, "foo", "2")
+	out := captureStdout(table.PrintData)
+	if out != "NAME SIZE\n---- ----\nfoo  2\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestRecomputeColSizes(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "foobarbaz", "1")
+	table.AddRow(2, "foo", "2")
+	table.SetTabWidth(1)
+
+	table.Data()[0][0] = "bar"
+	table.RecomputeColSizes()
+	out := captureStdout(table.PrintData)
+	if out != "bar 1\nfoo 2\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestSetCaption(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "foo", "1234567")
+	table.AddRow(2, "bar", "1")
+	table.SetTabWidth(1)
+	table.SetCaption("Generated at noon today")
+
+	out := captureStdout(table.PrintData)
+	if out != "foo 1234567\nbar 1\nGenerated\nat noon\ntoday\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+
+	if err := table.SetCaptionAlign(gocli.Align(-1)); err == nil {
+		t.Error("invalid alignment error")
+	}
+	table.SetCaptionAlign(gocli.AlignCenter)
+	table.SetCaption("Generated")
+	out = captureStdout(table.PrintData)
+	if out != "foo 1234567\nbar 1\n Generated\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestSetTabWidth(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "FOO", "BAR")
+	table.AddRow(2, "LONGER", "1")
+
+	if err := table.SetTabWidth(-1); err == nil {
+		t.Error("invalid tab width error")
+	}
+
+	table.SetTabWidth(4)
+	out := captureStdout(table.PrintData)
+	if out != "FOO     BAR\nLONGER  1\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func ExampleCli_AddCommand() {
+
+	// Init cli
+	var cli = gocli.Cli{
+		Name: "test",
+	}
+	cli.AddCommand("cp", "SRC DST", "Copy files")
+	cli.AddCommand("ls", "", "List files")
+	cli.Init()
+
+	cli.PrintUsage()
+	// Output:
+	// Usage: test [OPTIONS] COMMAND [arg...]
+	//
+	// Options:
+	//   --arg         : Arg flag (default "test")
+	//   -h, --help    : Display usage
+	//   -v, --version : Display version information
+	//
+	// Commands:
+	//   cp SRC DST    : Copy files
+	//   ls            : List files
+}
+
+func ExampleCli_Alias() {
+
+	// Init cli
+	var cli = gocli.Cli{
+		Name: "test",
+	}
+	cli.AddCommand("status", "", "Show status")
+	cli.AddCommand("checkout", "BRANCH", "Switch branches")
+	cli.Alias("st", "status")
+	cli.Alias("co", "checkout")
+
+	cli.InitArgs([]string{"st", "-s"})
+	fmt.Println(cli.SubCommand)
+
+	cli.PrintUsage()
+	// Output:
+	// status
+	// Usage: test [OPTIONS] COMMAND [arg...]
+	//
+	// Options:
+	//   --arg               : Arg flag (default "test")
+	//   -h, --help          : Display usage
+	//   -v, --version       : Display version information
+	//
+	// Commands:
+	//   checkout, co BRANCH : Switch branches
+	//   status, st          : Show status
+}
+
+func TestSetVariadic(t *testing.T) {
+
+	var cli = gocli.Cli{}
+	cli.AddCommand("copy", "DST", "Copy files")
+	cli.AddCommand("add", "", "Add files")
+	cli.SetVariadic("copy", "FILES")
+	cli.SetVariadic("add", "FILES")
+
+	cli.InitArgs([]string{"copy", "dir", "a.txt", "b.txt"})
+	if v := cli.Variadic(); len(v) != 2 || v[0] != "a.txt" || v[1] != "b.txt" {
+		t.Errorf("invalid variadic args: %v", v)
+	}
+
+	cli.InitArgs([]string{"copy", "dir"})
+	if v := cli.Variadic(); v != nil {
+		t.Errorf("invalid variadic args: %v", v)
+	}
+
+	cli.InitArgs([]string{"add", "a.txt"})
+	if v := cli.Variadic(); len(v) != 1 || v[0] != "a.txt" {
+		t.Errorf("invalid variadic args: %v", v)
+	}
+
+	out := captureStdout(func() { cli.PrintCommandUsage("copy") })
+	if !strings.HasPrefix(out, "Usage:  copy <DST> [FILES...]\n") {
+		t.Errorf("invalid command usage: %q", out)
+	}
+}
+
+func TestPrintUsage_required(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Name: "test",
+	}
+	cli.AddCommand("deploy", "ENV FILES...", "Deploy")
+	cli.AddCommand("status", "ENV", "Status")
+	cli.CommandFlags("deploy").String("region", "", "Region")
+	cli.CommandFlags("deploy").Bool("dry", false, "Dry run")
+	cli.RequireFlags("deploy", "region")
+
+	out := captureStdout(func() { cli.PrintCommandUsage("deploy") })
+	if out != "Usage: test deploy <--region> [OPTIONS] <ENV> [FILES...]\n\nDeploy\n\nOptions:\n  --dry    : Dry run\n  --region : Region (required)\n\n" {
+		t.Errorf("invalid command usage: %q", out)
+	}
+
+	out = captureStdout(func() { cli.PrintCommandUsage("status") })
+	if out != "Usage: test status ENV\n\nStatus\n\n" {
+		t.Errorf("invalid command usage: %q", out)
+	}
+
+	out = captureStdout(cli.PrintUsage)
+	if !strings.Contains(out, "  deploy <--region> <ENV> [FILES...] : Deploy\n") || !strings.Contains(out, "  status ENV                         : Status\n") {
+		t.Errorf("invalid usage: %q", out)
+	}
+}
+
+func TestPrintUsageVerbose(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Name: "test",
+	}
+	cli.AddCommand("deploy", "", "Deploy")
+	cli.AddCommand("status", "", "Status")
+	cli.CommandFlags("deploy").String("region", "", "Region")
+	cli.CommandFlags("deploy").Bool("dry", false, "Dry run")
+
+	out := captureStdout(cli.PrintUsage)
+	if !strings.HasSuffix(out, "Commands:\n  deploy        : Deploy\n  status        : Status\n\n") {
+		t.Errorf("invalid usage: %q", out)
+	}
+
+	out = captureStdout(cli.PrintUsageVerbose)
+	if !strings.HasSuffix(out, "Commands:\n  deploy        : Deploy\n      --dry     : Dry run\n      --region  : Region\n  status        : Status\n\n") {
+		t.Errorf("invalid verbose usage: %q", out)
+	}
+}
+
+func TestPrintUsage_color(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Name:  "test",
+		Color: true,
+	}
+	cli.AddCommand("ls", "", "List files")
+
+	out := captureStdout(cli.PrintUsage)
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("invalid usage output: %q", out)
+	}
+
+	defer gocli.SetTerminal(true)()
+	out = captureStdout(cli.PrintUsage)
+	if !strings.Contains(out, "\n  \x1b[36m--arg\x1b[0m         : Arg flag") || !strings.Contains(out, "\n  \x1b[36mls\x1b[0m            : List files\n") {
+		t.Errorf("invalid usage output: %q", out)
+	}
+
+	os.Setenv("NO_COLOR", "1")
+	defer os.Unsetenv("NO_COLOR")
+	if out := captureStdout(cli.PrintUsage); strings.Contains(out, "\x1b[") {
+		t.Errorf("invalid usage output: %q", out)
+	}
+}
+
+func TestSetRowLimit(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "1")
+	table.AddRow(2, "2")
+	table.AddRow(3, "3")
+	table.AddRow(4, "4")
+
+	if err := table.SetRowLimit(-1); err == nil {
+		t.Error("invalid row limit error")
+	}
+
+	table.SetRowLimit(2)
+	out := captureStdout(table.PrintData)
+	if out != "1\n2\n… and 2 more\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+
+	table.SetRowLimit(4)
+	out = captureStdout(table.PrintData)
+	if out != "1\n2\n3\n4\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestSet(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "OLD")
+	table.Set([]string{"NAME", "STATUS"}, [][]string{
+		{"foo", "up"},
+		{"bar"},
+	})
+
+	if len(table.Header()) != 2 || table.Header()[1] != "STATUS" {
+		t.Error("invalid table header")
+	}
+
+	var tdata = table.Data()
+	if len(tdata) != 2 {
+		t.Fatal("invalid table data")
+	}
+	if tdata[0][0] != "foo" || tdata[0][1] != "up" {
+		t.Error("invalid table data")
+	}
+	if len(tdata[1]) != 2 || tdata[1][0] != "bar" || tdata[1][1] != "" {
+		t.Error("invalid table data")
+	}
+
+	out := captureStdout(table.PrintData)
+	if out != "NAME\tSTATUS\n----\t------\nfoo \tup\nbar \t\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestPrintData_ansi(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "\x1b[31mFOO\x1b[0m", "BAR")
+	table.AddRow(2, "ÇAĞ", "1")
+	table.AddRow(3, "\x1b]8;;http://example.com\x1b\\BA\x1b]8;;\x1b\\", "2")
+
+	table.SetTabWidth(4)
+	out := captureStdout(table.PrintData)
+	if out != "\x1b[31mFOO\x1b[0m BAR\nÇAĞ 1\n\x1b]8;;http://example.com\x1b\\BA\x1b]8;;\x1b\\  2\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestView(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "STATUS", "PORT")
+	table.AddRow(1, "foo", "up", "80")
+	table.AddRow(2, "longer name", "down")
+
+	var view = table.View(3, 1, 5)
+	if h := view.Header(); len(h) != 3 || h[0] != "PORT" || h[1] != "NAME" || h[2] != "" {
+		t.Errorf("invalid view header: %q", h)
+	}
+
+	var vdata = view.Data()
+	if len(vdata) != 2 {
+		t.Fatal("invalid view data")
+	}
+	if vdata[0][0] != "80" || vdata[0][1] != "foo" || vdata[0][2] != "" {
+		t.Errorf("invalid view data: %q", vdata[0])
+	}
+	if vdata[1][0] != "" || vdata[1][1] != "longer name" {
+		t.Errorf("invalid view data: %q", vdata[1])
+	}
+
+	// The original table is unmodified
+	if len(table.Header()) != 3 || table.Data()[0][0] != "foo" {
+		t.Error("invalid table data")
+	}
+
+	view.SetTabWidth(1)
+	out := captureStdout(view.PrintData)
+	if out != "PORT NAME        \n---- ----------- \n80   foo         \n     longer name \n" {
+		t.Errorf("invalid view output: %q", out)
+	}
+	// The settings are copied
+	table.SetSeparator(" | ")
+	table.SetStyle(gocli.StyleBox)
+	table.SetCaption("Ports")
+	view = table.View(1, 3)
+	out = captureStdout(view.PrintData)
+	if out != "┌─────────────┬──────┐\n│ NAME        │ PORT │\n┝━━━━━━━━━━━━━┿━━━━━━┥\n│ foo         │ 80   │\n│ longer name │      │\n└─────────────┴──────┘\nPorts\n" {
+		t.Errorf("invalid view output: %q", out)
+	}
+
+	view.SetStyle(gocli.StylePlain)
+	view.SetCaption("")
+	out = captureStdout(view.PrintData)
+	if out != "NAME        | PORT\n----------- | ----\nfoo         | 80\nlonger name | \n" {
+		t.Errorf("invalid view output: %q", out)
+	}
+}
+
+func ExampleCli_PrintUsage_wrap() {
+
+	os.Setenv("COLUMNS", "40")
+	defer os.Unsetenv("COLUMNS")
+
+	// Init cli
+	var cli = gocli.Cli{
+		Name:        "test",
+		Description: "A long description that doesn't fit into the terminal width.\n\nShort paragraph.",
+	}
+	cli.Init()
+
+	cli.PrintUsage()
+	// Output:
+	// Usage: test [OPTIONS] COMMAND [arg...]
+	//
+	// A long description that doesn't fit into
+	// the terminal width.
+	//
+	// Short paragraph.
+	//
+	// Options:
+	//   --arg         : Arg flag (default "test")
+	//   -h, --help    : Display usage
+	//   -v, --version : Display version information
+}
+
+func TestSetFixedColWidth(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "STATUS")
+	table.AddRow(1, "foo", "running")
+	table.AddRow(2, "\x1b[31mbarbaz\x1b[0m", "up")
+
+	if err := table.SetFixedColWidth(0, 1); err == nil {
+		t.Error("invalid column index error")
+	}
+
+	table.SetFixedColWidth(1, 5)
+	table.SetFixedColWidth(2, 4)
+	table.SetTabWidth(1)
+	out := captureStdout(table.PrintData)
+	if out != "NAME  STAT\n----- ----\nfoo   runn\n\x1b[31mbarba\x1b[0m up  \n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+
+	// Fixed width records
+	table = gocli.Table{}
+	table.AddRow(1, "a", "x")
+	table.AddRow(2, "bbb", "yy")
+	table.SetSeparator("|")
+	table.SetFixedColWidth(2, 5)
+	out = captureStdout(table.PrintData)
+	if out != "a  |x    \nbbb|yy   \n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestSetMaxColWidth(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "DESCRIPTION")
+	table.AddRow(1, "foo", "a long description")
+	table.AddRow(2, "bar", "short")
+
+	if err := table.SetMaxColWidth(0, 1); err == nil {
+		t.Error("invalid column index error")
+	}
+
+	table.SetMaxColWidth(1, 10)
+	table.SetMaxColWidth(2, 8)
+	table.SetTabWidth(1)
+	out := captureStdout(table.PrintData)
+	if out != "NAME DESCRIP…\n---- --------\nfoo  a long …\nbar  short\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+
+	table.SetMaxColWidth(2, 0)
+	out = captureStdout(table.PrintData)
+	if out != "NAME DESCRIPTION\n---- ------------------\nfoo  a long description\nbar  short\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestSetStyle(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "SIZE")
+	table.AddRow(1, "foo", "10")
+	table.AddRow(2, "bar")
+	table.SetColAlign(2, gocli.AlignRight)
+
+	if err := table.SetStyle(gocli.TableStyle(9)); err == nil {
+		t.Error("invalid table style error")
+	}
+
+	table.SetStyle(gocli.StyleBox)
+	out := captureStdout(table.PrintData)
+	if out != "┌──────┬──────┐\n│ NAME │ SIZE │\n┝━━━━━━┿━━━━━━┥\n│ foo  │   10 │\n│ bar  │      │\n└──────┴──────┘\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+
+	table.SetStyle(gocli.StylePlain)
+	table.SetTabWidth(1)
+	out = captureStdout(table.PrintData)
+	if out != "NAME SIZE\n---- ----\nfoo    10\nbar\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestSetFooter(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "SIZE")
+	table.AddRow(1, "foo", "10")
+	table.AddRow(2, "bar", "5")
+	table.SetColAlign(2, gocli.AlignRight)
+	table.SetTabWidth(1)
+
+	out := captureStdout(table.PrintData)
+	if out != "NAME SIZE\n---- ----\nfoo    10\nbar     5\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+
+	table.SetFooter("Total", "15")
+	out = captureStdout(table.PrintData)
+	if out != "NAME  SIZE\n----- ----\nfoo     10\nbar      5\n----- ----\nTotal   15\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+
+	table.SetStyle(gocli.StyleBox)
+	out = captureStdout(table.PrintData)
+	if out != "┌───────┬──────┐\n│ NAME  │ SIZE │\n┝━━━━━━━┿━━━━━━┥\n│ foo   │   10 │\n│ bar   │    5 │\n├───────┼──────┤\n│ Total │   15 │\n└───────┴──────┘\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+	table.SetStyle(gocli.StylePlain)
+
+	var buf bytes.Buffer
+	if err := table.PrintRange(&buf, 2, 2); err != nil || buf.String() != "NAME  SIZE\n----- ----\nbar      5\n----- ----\nTotal   15\n" {
+		t.Errorf("invalid range output: %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := table.WriteCSV(&buf); err != nil || buf.String() != "NAME,SIZE\nfoo,10\nbar,5\n" {
+		t.Errorf("invalid csv output: %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := table.WriteNDJSON(&buf); err != nil || strings.Contains(buf.String(), "Total") {
+		t.Errorf("invalid ndjson output: %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := table.WriteMarkdown(&buf); err != nil || !strings.HasSuffix(buf.String(), "| bar | 5 |\n| Total | 15 |\n") {
+		t.Errorf("invalid markdown output: %q", buf.String())
+	}
+
+	table.Clear()
+	out = captureStdout(table.PrintData)
+	if out != "NAME SIZE\n---- ----\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestSetSeparator(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "SIZE", "NOTE")
+	table.AddRow(1, "foo", "10", "ok")
+	table.AddRow(2, "longer", "5")
+
+	table.SetSeparator("  ")
+	out := captureStdout(table.PrintData)
+	if out != "NAME    SIZE  NOTE\n------  ----  ----\nfoo     10    ok\nlonger  5\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+
+	if err := table.SetPadding(-1); err == nil {
+		t.Error("invalid padding error")
+	}
+
+	table.SetSeparator("|")
+	table.SetPadding(1)
+	out = captureStdout(table.PrintData)
+	if out != "NAME   |SIZE |NOTE\n------ |---- |----\nfoo    |10   |ok\nlonger |5\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestSetHeaderGroups(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "Q1", "Q2", "NOTE")
+	table.AddRow(1, "a", "10", "20", "ok")
+
+	if err := table.SetHeaderGroups([]gocli.HeaderGroup{{"", 0}}); err == nil {
+		t.Error("invalid header group span error")
+	}
+
+	table.SetHeaderGroups([]gocli.HeaderGroup{{"", 1}, {"REVENUE", 1}, {"REVENUE", 1}})
+	table.SetTabWidth(1)
+	out := captureStdout(table.PrintData)
+	if out != "     REVENUE     \nNAME Q1 Q2   NOTE\n---- -- ---- ----\na    10 20   ok\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestPrintRange(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "SIZE")
+	table.AddRow(1, "a", "1")
+	table.AddRow(2, "b", "22")
+	table.AddRow(3, "longname", "333")
+	table.SetTabWidth(1)
+
+	var buf bytes.Buffer
+	if err := table.PrintRange(&buf, 2, 5); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "NAME     SIZE\n-------- ----\nb        22\nlongname 333\n" {
+		t.Errorf("invalid table output: %q", buf.String())
+	}
+
+	if err := table.PrintRange(&buf, 0, 1); err == nil {
+		t.Error("invalid row range error")
+	}
+	if err := table.PrintRange(&buf, 2, 1); err == nil {
+		t.Error("invalid row range error")
+	}
+}
+
+func TestSetRowIndent(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "root", "1.0")
+	table.AddRow(2, "child", "2.0")
+	table.AddRow(3, "leaf", "3.0")
+
+	if err := table.SetRowIndent(0, 1); err == nil {
+		t.Error("invalid row index error")
+	}
+
+	table.SetRowIndent(2, 1)
+	table.SetRowIndent(3, 2)
+	table.SetTabWidth(1)
+	out := captureStdout(table.PrintData)
+	if out != "root     1.0\n  child  2.0\n    leaf 3.0\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+	if table.Data()[1][0] != "child" {
+		t.Error("invalid table data")
+	}
+}
+
+func TestSetRowStyle(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "foo", "OK")
+	table.AddRow(2, "bar", "ERROR")
+	table.SetRowStyle(func(row []string) (gocli.Color, bool) {
+		return gocli.ColorRed, row[1] == "ERROR"
+	})
+	table.SetTabWidth(1)
+
+	defer gocli.SetTerminal(true)()
+	out := captureStdout(table.PrintData)
+	if out != "foo OK\nbar ERROR\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+
+	table.SetColor(true)
+	out = captureStdout(table.PrintData)
+	if out != "foo OK\n\x1b[31mbar\x1b[0m \x1b[31mERROR\x1b[0m\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestSetCellLink(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "foo", "docs")
+	table.AddRow(2, "bar", "site")
+
+	if err := table.SetCellLink(0, 1, "http://example.com"); err == nil {
+		t.Error("invalid row index error")
+	}
+
+	table.SetCellLink(1, 2, "http://example.com/docs")
+	table.SetTabWidth(1)
+
+	defer gocli.SetTerminal(false)()
+	out := captureStdout(table.PrintData)
+	if out != "foo docs\nbar site\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+
+	gocli.SetTerminal(true)
+	out = captureStdout(table.PrintData)
+	if out != "foo \x1b]8;;http://example.com/docs\x1b\\docs\x1b]8;;\x1b\\\nbar site\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+
+	var buf bytes.Buffer
+	table.WriteTo(&buf)
+	if buf.String() != "foo docs\nbar site\n" {
+		t.Errorf("invalid table output: %q", buf.String())
+	}
+}
+
+func TestSetStatusColors(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "foo", "OK")
+	table.AddRow(2, "bar", "fail")
+	table.SetStatusColors(map[string]gocli.Color{
+		"ok":   gocli.ColorGreen,
+		"FAIL": gocli.ColorRed,
+	})
+	table.SetTabWidth(1)
+
+	defer gocli.SetTerminal(true)()
+	out := captureStdout(table.PrintData)
+	if out != "foo OK\nbar fail\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+
+	table.SetColor(true)
+	out = captureStdout(table.PrintData)
+	if out != "foo \x1b[32mOK\x1b[0m\nbar \x1b[31mfail\x1b[0m\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+
+	// The colors are printed by the writer instead of the stdout
+	var buf bytes.Buffer
+	table.WriteTo(&buf)
+	if buf.String() != "foo OK\nbar fail\n" {
+		t.Errorf("invalid table output: %q", buf.String())
+	}
+
+	os.Setenv("NO_COLOR", "1")
+	defer os.Unsetenv("NO_COLOR")
+	out = captureStdout(table.PrintData)
+	if out != "foo OK\nbar fail\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestInitArgs_nested(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Name: "test",
+		Commands: map[string]string{
+			"config get": "Get a config value",
+			"config set": "Set a config value",
+			"remote":     "Manage remotes",
+			"remote add": "Add a remote",
+			"version":    "Print version",
+		},
+	}
+	var ran string
+	for c := range cli.Commands {
+		c := c
+		cli.Handle(c, cli.Commands[c], func(cl *gocli.Cli) error {
+			ran = c
+			return nil
+		})
+	}
+
+	cli.InitArgs([]string{"config", "get", "name", "get"})
+	if cli.SubCommand != "config" || cli.SubSubCommand != "get" || len(cli.SubCommandArgs) != 2 || cli.SubCommandArgs[0] != "name" {
+		t.Errorf("invalid command: %q %q %q", cli.SubCommand, cli.SubSubCommand, cli.SubCommandArgs)
+	}
+	if err := cli.Run(); err != nil || ran != "config get" {
+		t.Errorf("invalid run: %q %v", ran, err)
+	}
+
+	cli.InitArgs([]string{"remote", "origin"})
+	if cli.SubCommand != "remote" || cli.SubSubCommand != "" || len(cli.SubCommandArgs) != 1 {
+		t.Errorf("invalid command: %q %q %q", cli.SubCommand, cli.SubSubCommand, cli.SubCommandArgs)
+	}
+
+	cli.InitArgs([]string{"config"})
+	if err := cli.Run(); err == nil || err.Error() != `missing subcommand of command "config"` {
+		t.Errorf("invalid missing subcommand error: %v", err)
+	}
+	cli.InitArgs([]string{"config", "gte"})
+	if err := cli.Run(); err == nil || err.Error() != `unknown command "config gte"; did you mean "config get"?` {
+		t.Errorf("invalid unknown command error: %v", err)
+	}
+
+	out := captureStdout(cli.PrintUsage)
+	if !strings.Contains(out, "Commands:\n  config        : \n    get         : Get a config value\n    set         : Set a config value\n  remote        : Manage remotes\n    add         : Add a remote\n  version       : Print version\n") {
+		t.Errorf("invalid usage output: %q", out)
+	}
+}
+
+func TestPrintUsage_keepOrder(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Name: "test",
+		Commands: map[string]string{
+			"version": "Print version",
+		},
+	}
+	cli.AddCommand("init", "", "Init the project")
+	cli.AddCommand("build", "", "Build the project")
+	cli.AddCommand("remote add", "", "Add a remote")
+	cli.AddCommand("deploy", "", "Deploy the project")
+	cli.AddCommand("remote list", "", "List the remotes")
+
+	out := captureStdout(cli.PrintUsage)
+	if !strings.Contains(out, "Commands:\n  build         : Build the project\n  deploy        : Deploy the project\n  init          : Init the project\n") {
+		t.Errorf("invalid usage output: %q", out)
+	}
+
+	cli.KeepUsageOrder = true
+	out = captureStdout(cli.PrintUsage)
+	if !strings.Contains(out, "Commands:\n  version       : Print version\n  init          : Init the project\n  build         : Build the project\n  remote        : \n    add         : Add a remote\n    list        : List the remotes\n  deploy        : Deploy the project\n") {
+		t.Errorf("invalid usage output: %q", out)
+	}
 }
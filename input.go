@@ -0,0 +1,251 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SetInput sets the input reader of the interactive helpers (defaults to os.Stdin)
+func (cl *Cli) SetInput(r io.Reader) {
+	cl.input = bufio.NewReader(r)
+}
+
+// StdinPiped reports whether the standard input is piped or redirected instead of being a terminal
+func (cl Cli) StdinPiped() bool {
+
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice == 0
+}
+
+// ReadStdin reads the standard input (or the input set by SetInput) fully
+func (cl *Cli) ReadStdin() ([]byte, error) {
+
+	if cl.input == nil {
+		cl.input = bufio.NewReader(os.Stdin)
+	}
+
+	return ioutil.ReadAll(cl.input)
+}
+
+// Prompt prints the given label and returns the entered value
+// The given default value is returned for an empty input
+func (cl *Cli) Prompt(label, def string) (string, error) {
+
+	if def != "" {
+		fmt.Fprintf(cl.stdout(), "%s [%s]: ", label, def)
+	} else {
+		fmt.Fprintf(cl.stdout(), "%s: ", label)
+	}
+
+	line, err := cl.readLine()
+	if err != nil {
+		return "", err
+	}
+
+	if line = strings.TrimSpace(line); line == "" {
+		return def, nil
+	}
+
+	return line, nil
+}
+
+// Confirm prints the given yes/no question and returns the answer
+// The given default answer is returned for an empty input
+func (cl *Cli) Confirm(label string, def bool) (bool, error) {
+
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+
+	// Ask until a valid answer is given
+	for {
+		fmt.Fprintf(cl.stdout(), "%s [%s]: ", label, hint)
+
+		line, err := cl.readLine()
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+// PromptInt prints the given label and returns the entered integer
+// It asks again until the input is an integer that passes the given validation (if any)
+// and returns an error only if the input can't be read (i.e. io.EOF).
+func (cl *Cli) PromptInt(label string, validate func(int) error) (int, error) {
+
+	// Ask until a valid integer is given
+	for {
+		fmt.Fprintf(cl.stdout(), "%s: ", label)
+
+		line, err := cl.readLine()
+		if err != nil {
+			return 0, err
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(line))
+		if err != nil {
+			fmt.Fprintf(cl.stdout(), "invalid integer %q\n", strings.TrimSpace(line))
+			continue
+		}
+		if validate != nil {
+			if err := validate(n); err != nil {
+				fmt.Fprintln(cl.stdout(), err)
+				continue
+			}
+		}
+
+		return n, nil
+	}
+}
+
+// Select prints the given label and options, and returns the index of the selected option
+func (cl *Cli) Select(label string, options []string) (int, error) {
+
+	if len(options) == 0 {
+		return -1, errors.New("missing options")
+	}
+
+	// Ask until a valid option is selected
+	for {
+		fmt.Fprintln(cl.stdout(), label)
+		for i, o := range options {
+			fmt.Fprintf(cl.stdout(), "  %d) %s\n", i+1, o)
+		}
+		fmt.Fprintf(cl.stdout(), "Select [1-%d]: ", len(options))
+
+		line, err := cl.readLine()
+		if err != nil {
+			return -1, err
+		}
+
+		if n, err := strconv.Atoi(strings.TrimSpace(line)); err == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+	}
+}
+
+// REPL reads the commands line by line from the input and runs them until
+// the end of the input or a `quit` or `exit` command
+func (cl *Cli) REPL(prompt string) error {
+
+	for {
+		fmt.Fprint(cl.stdout(), prompt)
+
+		line, err := cl.readLine()
+		if err == io.EOF {
+			fmt.Fprintln(cl.stdout())
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		args, err := splitLine(line)
+		if err != nil {
+			fmt.Fprintln(cl.stderr(), err)
+			continue
+		}
+
+		// If it's an empty line or the end of the session then
+		if len(args) == 0 {
+			continue
+		}
+		if _, ok := cl.Commands[args[0]]; !ok && (args[0] == "quit" || args[0] == "exit") {
+			return nil
+		}
+
+		// Run the command
+		// The usage is printed by Run if there is no command so ErrNoCommand isn't reported
+		cl.startTime = time.Now()
+		cl.InitArgs(args)
+		if err := cl.Run(); err != nil && err != ErrNoCommand {
+			fmt.Fprintln(cl.stderr(), err)
+		}
+	}
+}
+
+// splitLine splits the given line into args like a shell does
+// Single quotes, double quotes and backslash escapes are supported
+func splitLine(line string) ([]string, error) {
+
+	var args []string
+	var arg []rune
+	var inArg, escaped bool
+	var quote rune
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			arg = append(arg, r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg = append(arg, r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, string(arg))
+				arg, inArg = nil, false
+			}
+		default:
+			arg = append(arg, r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inArg {
+		args = append(args, string(arg))
+	}
+
+	return args, nil
+}
+
+// readLine reads a line from the input without the line ending
+func (cl *Cli) readLine() (string, error) {
+
+	if cl.input == nil {
+		cl.input = bufio.NewReader(os.Stdin)
+	}
+
+	line, err := cl.input.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+
+	return strings.TrimRight(line, "\r\n"), err
+}
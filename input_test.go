@@ -0,0 +1,215 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/yieldbot/gocli"
+)
+
+func TestPrompt(t *testing.T) {
+
+	var cli = gocli.Cli{}
+	cli.SetInput(strings.NewReader("foo\n\n"))
+
+	var val string
+	var err error
+	out := captureStdout(func() {
+		val, err = cli.Prompt("Name", "")
+	})
+	if err != nil || val != "foo" {
+		t.Error("invalid prompt value")
+	}
+	if out != "Name: " {
+		t.Errorf("invalid prompt output: %q", out)
+	}
+
+	out = captureStdout(func() {
+		val, err = cli.Prompt("Name", "bar")
+	})
+	if err != nil || val != "bar" {
+		t.Error("invalid prompt default value")
+	}
+	if out != "Name [bar]: " {
+		t.Errorf("invalid prompt output: %q", out)
+	}
+
+	captureStdout(func() {
+		_, err = cli.Prompt("Name", "")
+	})
+	if err != io.EOF {
+		t.Error("invalid prompt error")
+	}
+}
+
+func TestConfirm(t *testing.T) {
+
+	var cli = gocli.Cli{}
+	cli.SetInput(strings.NewReader("maybe\nYes\n\nn"))
+
+	var ok bool
+	var err error
+	captureStdout(func() {
+		ok, err = cli.Confirm("Continue?", false)
+	})
+	if err != nil || !ok {
+		t.Error("invalid confirm answer")
+	}
+
+	captureStdout(func() {
+		ok, err = cli.Confirm("Continue?", true)
+	})
+	if err != nil || !ok {
+		t.Error("invalid confirm default answer")
+	}
+
+	captureStdout(func() {
+		ok, err = cli.Confirm("Continue?", true)
+	})
+	if err != nil || ok {
+		t.Error("invalid confirm answer")
+	}
+}
+
+func TestSelect(t *testing.T) {
+
+	var cli = gocli.Cli{}
+	cli.SetInput(strings.NewReader("3\n2\n"))
+
+	var i int
+	var err error
+	out := captureStdout(func() {
+		i, err = cli.Select("Color", []string{"red", "green"})
+	})
+	if err != nil || i != 1 {
+		t.Error("invalid selected option")
+	}
+	if !strings.HasPrefix(out, "Color\n  1) red\n  2) green\nSelect [1-2]: ") {
+		t.Errorf("invalid select output: %q", out)
+	}
+
+	if _, err := cli.Select("Color", nil); err == nil {
+		t.Error("invalid select error")
+	}
+}
+
+func TestPromptInt(t *testing.T) {
+
+	var cli = gocli.Cli{}
+	cli.SetInput(strings.NewReader("abc\n-1\n5\n"))
+
+	var n int
+	var err error
+	out := captureStdout(func() {
+		n, err = cli.PromptInt("Count", func(n int) error {
+			if n < 0 {
+				return errors.New("must be positive")
+			}
+			return nil
+		})
+	})
+	if err != nil || n != 5 {
+		t.Error("invalid prompted integer")
+	}
+	if out != "Count: invalid integer \"abc\"\nCount: must be positive\nCount: " {
+		t.Errorf("invalid prompt output: %q", out)
+	}
+
+	if _, err := cli.PromptInt("Count", nil); err != io.EOF {
+		t.Error("invalid EOF error")
+	}
+}
+
+func TestREPL(t *testing.T) {
+
+	var buf bytes.Buffer
+	var cli = gocli.Cli{
+		Stderr: &buf,
+	}
+	var runs [][]string
+	var debugs []bool
+	cli.Handle("cmd", "Test command", func(c *gocli.Cli) error {
+		runs = append(runs, c.SubCommandArgs)
+		debugs = append(debugs, c.Debug)
+		return nil
+	})
+	cli.SetInput(strings.NewReader("cmd a 'b c'\n\nbogus\ncmd \"d\n  cmd --e=f\ncdm\ncmd --debug\ncmd\nexit\ncmd g\n"))
+
+	var err error
+	out := captureStdout(func() {
+		err = cli.REPL("> ")
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if out != "> > > > > > > > > " {
+		t.Errorf("invalid REPL output: %q", out)
+	}
+	if !strings.HasPrefix(buf.String(), "unknown command \"bogus\"\n") || !strings.Contains(buf.String(), "unknown command \"cdm\"; did you mean \"cmd\"?\n") {
+		t.Errorf("invalid REPL errors: %q", buf.String())
+	}
+
+	if len(runs) != 4 {
+		t.Fatalf("invalid REPL runs: %q", runs)
+	}
+	if debugs[2] != true || debugs[3] != false {
+		t.Errorf("invalid REPL debug modes: %v", debugs)
+	}
+	if len(runs[0]) != 2 || runs[0][0] != "a" || runs[0][1] != "b c" {
+		t.Errorf("invalid REPL args: %q", runs[0])
+	}
+	if len(runs[1]) != 1 || runs[1][0] != "--e=f" {
+		t.Errorf("invalid REPL args: %q", runs[1])
+	}
+
+	// End of the input
+	cli.SetInput(strings.NewReader("cmd h"))
+	captureStdout(func() {
+		err = cli.REPL("> ")
+	})
+	if err != nil || len(runs) != 5 {
+		t.Error("invalid REPL end of input")
+	}
+}
+
+func TestReadStdin(t *testing.T) {
+
+	// Pipe the stdin
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() {
+		os.Stdin = stdin
+		r.Close()
+	}()
+	w.WriteString("foo\nbar\n")
+	w.Close()
+
+	var cli = gocli.Cli{}
+	if !cli.StdinPiped() {
+		t.Error("invalid piped stdin")
+	}
+
+	data, err := cli.ReadStdin()
+	if err != nil || string(data) != "foo\nbar\n" {
+		t.Errorf("invalid stdin data: %q %v", data, err)
+	}
+
+	cli.SetInput(strings.NewReader("baz"))
+	if data, _ := cli.ReadStdin(); string(data) != "baz" {
+		t.Errorf("invalid stdin data: %q", data)
+	}
+}
@@ -0,0 +1,225 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ErrNoCommand is returned by Run when there is no subcommand to run
+var ErrNoCommand = errors.New("no command")
+
+// Handler represents a command handler
+type Handler func(*Cli) error
+
+// Handle registers the given handler by the given command name and description
+func (cl *Cli) Handle(command, desc string, fn Handler) {
+
+	cl.addCommand(command, desc)
+
+	if cl.handlers == nil {
+		cl.handlers = make(map[string]Handler)
+	}
+	cl.handlers[command] = fn
+}
+
+// Precondition adds the given check to the given command
+// Run runs all the checks of the command before its handler and
+// returns their errors instead of running the handler
+func (cl *Cli) Precondition(command string, check func() error) {
+
+	if cl.preconditions == nil {
+		cl.preconditions = make(map[string][]func() error)
+	}
+	cl.preconditions[command] = append(cl.preconditions[command], check)
+}
+
+// SetMetricsHook sets the function that is called after each command run by Run
+// It receives the command name, the elapsed time and the error of the handler
+func (cl *Cli) SetMetricsHook(fn func(command string, dur time.Duration, err error)) {
+	cl.metricsHook = fn
+}
+
+// Run runs the handler of the subcommand and returns its error
+// If the help is requested then it prints the usage of the cli or the command (see IsHelpRequested).
+// If there is no subcommand then it prints the usage and returns ErrNoCommand.
+// An unknown command is reported by an error with the closest command as a suggestion.
+func (cl *Cli) Run() error {
+
+	// If the help is requested then print the usage
+	if cl.IsHelpRequested() {
+		if cl.helpCommand == "" {
+			cl.PrintUsage()
+			return nil
+		}
+		if !cl.isCommand(cl.helpCommand) {
+			return cl.unknownCommandError(cl.helpCommand)
+		}
+		cl.PrintCommandUsage(cl.helpCommand)
+		return nil
+	}
+
+	if cl.SubCommand == "" && cl.unknownCommand != "" {
+		return cl.unknownCommandError(cl.unknownCommand)
+	}
+	if cl.SubCommand == "" {
+		cl.PrintUsage()
+		return ErrNoCommand
+	}
+
+	// If the command is only the parent of the second level commands then
+	if _, ok := cl.Commands[cl.command()]; !ok {
+		if len(cl.SubCommandPositional) > 0 {
+			return cl.unknownCommandError(cl.SubCommand + " " + cl.SubCommandPositional[0])
+		}
+		return &ParseError{Err: ErrMissingArg, Name: cl.SubCommand, msg: fmt.Sprintf("missing subcommand of command %q", cl.SubCommand)}
+	}
+
+	fn, ok := cl.handlers[cl.command()]
+	if !ok || fn == nil {
+		return fmt.Errorf("missing handler for command %q", cl.command())
+	}
+
+	// Check the args
+	if cl.argsErr != nil {
+		return &ParseError{Err: ErrInvalidFlag, Name: flagErrorName(cl.argsErr), msg: cl.argsErr.Error()}
+	}
+
+	// Check the preconditions
+	var errs []string
+	for _, check := range cl.preconditions[cl.command()] {
+		if err := check(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if err := joinErrors(errs); err != nil {
+		cl.debugf("preconditions of command %q failed: %s", cl.command(), err)
+		return err
+	}
+
+	// Run the handler
+	cl.debugf("running command %q", cl.command())
+	start := time.Now()
+	err := fn(cl)
+
+	if cl.metricsHook != nil {
+		cl.metricsHook(cl.command(), time.Since(start), err)
+	}
+	if cl.Timing {
+		if !cl.startTime.IsZero() {
+			start = cl.startTime
+		}
+		fmt.Fprintf(cl.stderr(), "Completed in %.2fs\n", time.Since(start).Seconds())
+	}
+
+	return err
+}
+
+// unknownCommandError returns the error of the given unknown command with a suggestion if any
+func (cl Cli) unknownCommandError(command string) error {
+
+	msg := fmt.Sprintf("unknown command %q", command)
+	if s, ok := cl.SuggestCommand(command); ok {
+		msg += fmt.Sprintf("; did you mean %q?", s)
+	}
+
+	return &ParseError{Err: ErrUnknownCommand, Name: command, msg: msg}
+}
+
+// RunAndExit runs the handler of the subcommand and exits by its error
+func (cl *Cli) RunAndExit() {
+	cl.Exit(cl.Run())
+}
+
+// ExitCoder is the interface implemented by the errors which have their own exit codes
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// MapExitCode sets the exit code of the given error for Exit
+// The error is matched by equality against the error passed to Exit and its causes (see Unwrap).
+// The errors of the non-comparable types (i.e. slices) are never matched.
+func (cl *Cli) MapExitCode(err error, code int) {
+
+	for i, m := range cl.exitCodes {
+		if sameError(m.err, err) {
+			cl.exitCodes[i].code = code
+			return
+		}
+	}
+	cl.exitCodes = append(cl.exitCodes, exitCodeMapping{err: err, code: code})
+}
+
+// exitCodeMapping represents an exit code that is mapped to an error by MapExitCode
+type exitCodeMapping struct {
+	err  error
+	code int
+}
+
+// sameError reports whether the given errors are equal
+// The errors whose types are not comparable are never equal for avoiding the runtime panic.
+func sameError(a, b error) bool {
+
+	if a == nil || b == nil {
+		return a == b
+	}
+	if !reflect.TypeOf(a).Comparable() || !reflect.TypeOf(b).Comparable() {
+		return false
+	}
+
+	return a == b
+}
+
+// Exit exits by the given error
+// A nil error exits with the code 0, otherwise the error is printed and the exit code is resolved by order;
+// the code mapped by MapExitCode to the error or its first mapped cause,
+// the code of the first error in the chain that implements ExitCoder, and finally 1.
+func (cl *Cli) Exit(err error) {
+
+	if err == nil {
+		cl.exit(0)
+		return
+	}
+
+	fmt.Fprintln(cl.stderr(), err)
+	cl.exit(cl.exitCode(err))
+}
+
+// exitCode returns the exit code of the given error
+func (cl Cli) exitCode(err error) int {
+
+	// Check the mapped codes
+	for e := err; e != nil; e = unwrap(e) {
+		for _, m := range cl.exitCodes {
+			if sameError(m.err, e) {
+				return m.code
+			}
+		}
+	}
+
+	// Check the errors that carry their codes
+	for e := err; e != nil; e = unwrap(e) {
+		if ec, ok := e.(ExitCoder); ok {
+			return ec.ExitCode()
+		}
+	}
+
+	return 1
+}
+
+// unwrap returns the cause of the given error if any
+func unwrap(err error) error {
+	if u, ok := err.(interface {
+		Unwrap() error
+	}); ok {
+		return u.Unwrap()
+	}
+	return nil
+}
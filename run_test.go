@@ -0,0 +1,328 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yieldbot/gocli"
+)
+
+func TestRun(t *testing.T) {
+
+	// Reset the args
+	setArgs("cmd", "arg1")
+
+	// Init cli
+	var cli = gocli.Cli{}
+	var runArgs []string
+	cli.Handle("cmd", "Test command", func(c *gocli.Cli) error {
+		runArgs = c.SubCommandArgs
+		return errors.New("cmd error")
+	})
+
+	var hookCmd string
+	var hookErr error
+	cli.SetMetricsHook(func(command string, dur time.Duration, err error) {
+		hookCmd = command
+		hookErr = err
+	})
+	cli.Init()
+
+	if cli.Commands["cmd"] != "Test command" {
+		t.Error("invalid Commands")
+	}
+
+	if err := cli.Run(); err == nil || err.Error() != "cmd error" {
+		t.Error("invalid Run error")
+	}
+
+	if len(runArgs) != 1 || runArgs[0] != "arg1" {
+		t.Error("invalid handler args")
+	}
+
+	if hookCmd != "cmd" || hookErr == nil {
+		t.Error("invalid metrics hook")
+	}
+}
+
+func TestRun_noCommand(t *testing.T) {
+
+	// Reset the args
+	setArgs()
+
+	// Init cli
+	var buf bytes.Buffer
+	var cli = gocli.Cli{
+		Stdout: &buf,
+	}
+	cli.Handle("cmd", "Test command", func(c *gocli.Cli) error {
+		return nil
+	})
+	cli.Init()
+
+	if err := cli.Run(); err != gocli.ErrNoCommand {
+		t.Error("invalid Run error")
+	}
+	if !strings.HasPrefix(buf.String(), "Usage: ") {
+		t.Errorf("invalid usage output: %q", buf.String())
+	}
+}
+
+func TestRun_unknownCommand(t *testing.T) {
+
+	var cli = gocli.Cli{}
+	cli.Handle("delete", "Delete", func(c *gocli.Cli) error {
+		return nil
+	})
+
+	if s, ok := cli.SuggestCommand("DELTE"); !ok || s != "delete" {
+		t.Error("invalid suggested command")
+	}
+	if _, ok := cli.SuggestCommand("create"); ok {
+		t.Error("invalid suggested command")
+	}
+
+	cli.InitArgs([]string{"-h", "delet", "x"})
+	if err := cli.Run(); err == nil || err.Error() != `unknown command "delet"; did you mean "delete"?` {
+		t.Errorf("invalid Run error: %v", err)
+	}
+
+	cli.InitArgs([]string{"create"})
+	if err := cli.Run(); err == nil || err.Error() != `unknown command "create"` {
+		t.Errorf("invalid Run error: %v", err)
+	}
+}
+
+func TestRunAndExit(t *testing.T) {
+
+	// Reset the args
+	setArgs("cmd")
+
+	// Init cli
+	var cli = gocli.Cli{}
+	var cmdErr error
+	cli.Handle("cmd", "Test command", func(c *gocli.Cli) error {
+		return cmdErr
+	})
+	cli.Init()
+
+	var codes []int
+	cli.ExitFunc = func(code int) {
+		codes = append(codes, code)
+	}
+
+	cli.RunAndExit()
+	cmdErr = errors.New("cmd error")
+	cli.RunAndExit()
+	cli.Exit(nil)
+
+	if len(codes) != 3 || codes[0] != 0 || codes[1] != 1 || codes[2] != 0 {
+		t.Errorf("invalid exit codes: %v", codes)
+	}
+}
+
+type codeError int
+
+func (e codeError) Error() string { return "code error" }
+func (e codeError) ExitCode() int { return int(e) }
+
+func TestMapExitCode(t *testing.T) {
+
+	var buf bytes.Buffer
+	var cli = gocli.Cli{Stderr: &buf}
+	var codes []int
+	cli.ExitFunc = func(code int) {
+		codes = append(codes, code)
+	}
+
+	cli.MapExitCode(gocli.ErrUnknownCommand, 127)
+	cli.MapExitCode(gocli.ErrMissingArg, 2)
+	cli.Handle("cmd", "Test command", func(c *gocli.Cli) error {
+		return nil
+	})
+
+	cli.InitArgs([]string{"foo"})
+	cli.RunAndExit()
+	cli.Exit(&gocli.ParseError{Err: gocli.ErrMissingArg, Name: "region"})
+	cli.Exit(codeError(3))
+	cli.MapExitCode(codeError(3), 4)
+	cli.Exit(codeError(3))
+	cli.Exit(errors.New("error"))
+
+	if len(codes) != 5 || codes[0] != 127 || codes[1] != 2 || codes[2] != 3 || codes[3] != 4 || codes[4] != 1 {
+		t.Errorf("invalid exit codes: %v", codes)
+	}
+
+	// Unhashable errors
+	codes = nil
+	cli.MapExitCode(multiErr{codeError(3)}, 5)
+	cli.Exit(multiErr{codeError(3)})
+	cli.Exit(codeError(3))
+	if len(codes) != 2 || codes[0] != 1 || codes[1] != 4 {
+		t.Errorf("invalid exit codes: %v", codes)
+	}
+}
+
+type multiErr []error
+
+func (e multiErr) Error() string { return "multi error" }
+
+func TestPrecondition(t *testing.T) {
+
+	// Init cli
+	var cli = gocli.Cli{}
+	var runs int
+	cli.Handle("cmd", "Test command", func(c *gocli.Cli) error {
+		runs++
+		return nil
+	})
+	cli.Handle("other", "Other command", func(c *gocli.Cli) error {
+		runs++
+		return nil
+	})
+
+	var fail bool
+	cli.Precondition("cmd", func() error {
+		if fail {
+			return errors.New("missing config file")
+		}
+		return nil
+	})
+	cli.Precondition("cmd", func() error {
+		if fail {
+			return errors.New("missing TOKEN env var")
+		}
+		return nil
+	})
+
+	cli.InitArgs([]string{"cmd"})
+	if err := cli.Run(); err != nil || runs != 1 {
+		t.Error("invalid Run with preconditions")
+	}
+
+	fail = true
+	err := cli.Run()
+	if err == nil || err.Error() != "missing config file; missing TOKEN env var" {
+		t.Errorf("invalid precondition error: %v", err)
+	}
+	if runs != 1 {
+		t.Error("invalid Run with failed preconditions")
+	}
+
+	cli.InitArgs([]string{"other"})
+	if err := cli.Run(); err != nil || runs != 2 {
+		t.Error("invalid Run without preconditions")
+	}
+}
+
+func TestTiming(t *testing.T) {
+
+	var buf bytes.Buffer
+	var cli = gocli.Cli{
+		Stderr: &buf,
+	}
+	cli.Handle("cmd", "Test command", func(c *gocli.Cli) error {
+		return nil
+	})
+
+	cli.InitArgs([]string{"cmd"})
+	cli.Run()
+	if buf.Len() > 0 {
+		t.Errorf("invalid timing output: %q", buf.String())
+	}
+
+	cli.InitArgs([]string{"cmd", "timing"})
+	cli.Run()
+	if cli.Timing || buf.Len() > 0 {
+		t.Errorf("invalid timing output: %q", buf.String())
+	}
+
+	cli.InitArgs([]string{"cmd", "--timing"})
+	cli.Run()
+	if !cli.Timing || !strings.HasPrefix(buf.String(), "Completed in ") {
+		t.Errorf("invalid timing output: %q", buf.String())
+	}
+}
+
+func TestRun_help(t *testing.T) {
+
+	var buf bytes.Buffer
+	var cli = gocli.Cli{
+		Name:   "test",
+		Stdout: &buf,
+	}
+	cli.Handle("cp", "Copy files", func(c *gocli.Cli) error {
+		return errors.New("handler error")
+	})
+	cli.SetArgsHint("cp", "SRC DST")
+	cli.CommandFlags("cp").Bool("r", false, "Copy recursively")
+
+	cli.InitArgs([]string{"cp", "a", "b"})
+	if cli.IsHelpRequested() {
+		t.Error("invalid help request")
+	}
+
+	cli.InitArgs([]string{"help", "cp"})
+	if !cli.IsHelpRequested() || cli.SubCommand != "" {
+		t.Error("invalid help request")
+	}
+	if err := cli.Run(); err != nil {
+		t.Error(err)
+	}
+	if buf.String() != "Usage: test cp [OPTIONS] SRC DST\n\nCopy files\n\nOptions:\n  -r : Copy recursively\n\n" {
+		t.Errorf("invalid command usage: %q", buf.String())
+	}
+
+	buf.Reset()
+	cli.InitArgs([]string{"cp", "-h"})
+	if err := cli.Run(); err != nil || !strings.HasPrefix(buf.String(), "Usage: test cp") {
+		t.Errorf("invalid command usage: %q", buf.String())
+	}
+
+	buf.Reset()
+	cli.InitArgs([]string{"help"})
+	if err := cli.Run(); err != nil || !strings.HasPrefix(buf.String(), "Usage: test [OPTIONS]") {
+		t.Errorf("invalid usage: %q", buf.String())
+	}
+
+	cli.InitArgs([]string{"help", "pc"})
+	if err := cli.Run(); err == nil || err.Error() != `unknown command "pc"; did you mean "cp"?` {
+		t.Errorf("invalid Run error: %v", err)
+	}
+}
+
+func TestRun_helpArg(t *testing.T) {
+
+	var buf bytes.Buffer
+	var cli = gocli.Cli{
+		Name:   "test",
+		Stdout: &buf,
+	}
+	var args []string
+	cli.Handle("search", "Search the docs", func(c *gocli.Cli) error {
+		args = c.SubCommandPositional
+		return nil
+	})
+
+	cli.InitArgs([]string{"search", "help"})
+	if cli.IsHelpRequested() {
+		t.Error("invalid help request")
+	}
+	if err := cli.Run(); err != nil || len(args) != 1 || args[0] != "help" || buf.Len() != 0 {
+		t.Errorf("invalid search run: %q %v %q", args, err, buf.String())
+	}
+
+	cli.InitArgs([]string{"search", "--help"})
+	if !cli.IsHelpRequested() {
+		t.Error("invalid help request")
+	}
+}
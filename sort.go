@@ -0,0 +1,127 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SortByCol sorts the rows by the values of the given column with a stable sort
+// The values are compared numerically if all the non-blank values of the column are numeric.
+// The missing and blank values are sorted first in the ascending order. The header is not sorted
+// and the row settings (i.e. indents and links) are moved with their rows.
+func (t *Table) SortByCol(col int, desc bool) error {
+
+	if col < 1 {
+		return errors.New("invalid column index")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Collect the keys of the rows
+	rs := &rowSorter{
+		order:   make([]int, len(t.data)),
+		keys:    make([]string, len(t.data)),
+		nums:    make([]float64, len(t.data)),
+		numeric: true,
+		desc:    desc,
+	}
+	for i, row := range t.data {
+		rs.order[i] = i
+		if col <= len(row) {
+			rs.keys[i] = strings.TrimSpace(row[col-1])
+		}
+		if rs.keys[i] == "" || !rs.numeric {
+			continue
+		}
+		n, err := strconv.ParseFloat(rs.keys[i], 64)
+		if err != nil {
+			rs.numeric = false
+			continue
+		}
+		rs.nums[i] = n
+	}
+	sort.Stable(rs)
+
+	t.reorderRows(rs.order)
+
+	return nil
+}
+
+// reorderRows sets the rows by the given order of the row indices
+// The rows that are not in the order are removed and the row settings (i.e. indents and links) are moved with their rows.
+func (t *Table) reorderRows(order []int) {
+
+	pos := make(map[int]int, len(order))
+	data := make([][]string, len(order))
+	for i, r := range order {
+		data[i] = t.data[r]
+		pos[r] = i
+	}
+	t.data = data
+
+	if len(t.indents) > 0 {
+		indents := make(map[int]int, len(t.indents))
+		for r, level := range t.indents {
+			if p, ok := pos[r]; ok {
+				indents[p] = level
+			}
+		}
+		t.indents = indents
+	}
+	if len(t.links) > 0 {
+		links := make(map[[2]int]string, len(t.links))
+		for k, url := range t.links {
+			if p, ok := pos[k[0]]; ok {
+				links[[2]int{p, k[1]}] = url
+			}
+		}
+		t.links = links
+	}
+}
+
+// rowSorter sorts the row order by the keys of the rows
+type rowSorter struct {
+	order   []int
+	keys    []string
+	nums    []float64
+	numeric bool
+	desc    bool
+}
+
+func (rs *rowSorter) Len() int {
+	return len(rs.order)
+}
+
+func (rs *rowSorter) Swap(i, j int) {
+	rs.order[i], rs.order[j] = rs.order[j], rs.order[i]
+}
+
+func (rs *rowSorter) Less(i, j int) bool {
+	if rs.desc {
+		return rs.less(rs.order[j], rs.order[i])
+	}
+	return rs.less(rs.order[i], rs.order[j])
+}
+
+// less reports whether the given row is sorted before the other one in the ascending order
+func (rs *rowSorter) less(a, b int) bool {
+
+	// Blank values are first
+	if rs.keys[a] == "" || rs.keys[b] == "" {
+		return rs.keys[a] == "" && rs.keys[b] != ""
+	}
+
+	if rs.numeric {
+		return rs.nums[a] < rs.nums[b]
+	}
+	return rs.keys[a] < rs.keys[b]
+}
@@ -0,0 +1,40 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli_test
+
+import (
+	"testing"
+
+	"github.com/yieldbot/gocli"
+)
+
+func TestSortByCol(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "SIZE")
+	table.AddRow(1, "foo", "10")
+	table.AddRow(2, "bar", "9")
+	table.AddRow(3, "baz")
+	table.AddRow(4, "qux", "9")
+
+	if err := table.SortByCol(0, false); err == nil {
+		t.Error("invalid column index error")
+	}
+
+	table.SortByCol(2, false)
+	table.SetTabWidth(1)
+	out := captureStdout(table.PrintData)
+	if out != "NAME SIZE\n---- ----\nbaz\nbar  9\nqux  9\nfoo  10\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+
+	table.SortByCol(1, true)
+	out = captureStdout(table.PrintData)
+	if out != "NAME SIZE\n---- ----\nqux  9\nfoo  10\nbaz\nbar  9\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
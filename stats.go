@@ -0,0 +1,184 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AddTotalsRow adds a row that contains the sums of the given numeric columns
+// The first column of the row is set by the given label and the other columns are left blank
+func (t *Table) AddTotalsRow(label string, cols ...int) error {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	row := []string{label}
+	for _, col := range cols {
+
+		// Check the column number
+		if col < 2 {
+			return errors.New("invalid column index")
+		}
+
+		// Sum the column values by keeping the maximum precision
+		_, _, sum, _, err := t.colStats(col, false)
+		if err != nil {
+			return err
+		}
+		var prec int
+		for _, r := range t.data {
+			if col > len(r) {
+				continue
+			}
+			v := strings.TrimSpace(r[col-1])
+			if d := strings.Index(v, "."); d >= 0 && len(v)-d-1 > prec {
+				prec = len(v) - d - 1
+			}
+		}
+
+		// Grow the row and set the sum
+		for len(row) < col {
+			row = append(row, "")
+		}
+		row[col-1] = strconv.FormatFloat(sum, 'f', prec, 64)
+	}
+
+	return t.addRow(len(t.data)+1, row...)
+}
+
+// ColStats returns the minimum, maximum, sum and count of the numeric values of the given column
+// Blank cells are skipped and an error is returned for the non-numeric values.
+func (t *Table) ColStats(col int) (min, max, sum float64, count int, err error) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.colStats(col, false)
+}
+
+// colStats returns the stats of the given column by skipping or rejecting the non-numeric values
+func (t *Table) colStats(col int, skip bool) (min, max, sum float64, count int, err error) {
+
+	if col < 1 {
+		return 0, 0, 0, 0, errors.New("invalid column index")
+	}
+
+	for i, r := range t.data {
+		if col > len(r) || strings.TrimSpace(r[col-1]) == "" {
+			continue
+		}
+
+		n, err := strconv.ParseFloat(strings.TrimSpace(r[col-1]), 64)
+		if err != nil {
+			if skip {
+				continue
+			}
+			return 0, 0, 0, 0, fmt.Errorf("invalid numeric value %q at row %d, column %d", r[col-1], i+1, col)
+		}
+
+		if count == 0 || n < min {
+			min = n
+		}
+		if count == 0 || n > max {
+			max = n
+		}
+		sum += n
+		count++
+	}
+
+	return min, max, sum, count, nil
+}
+
+// Duplicates returns the groups of row indices which are identical across the given columns
+// All the columns are compared if none given. Groups are ordered by their first row.
+func (t *Table) Duplicates(cols ...int) [][]int {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Group the rows by their keys
+	groups := make(map[string][]int)
+	var keys []string
+	for i, r := range t.data {
+		var parts []string
+		if len(cols) == 0 {
+			parts = r
+		} else {
+			for _, col := range cols {
+				var v string
+				if col > 0 && col <= len(r) {
+					v = r[col-1]
+				}
+				parts = append(parts, v)
+			}
+		}
+
+		key := strings.Join(parts, "\x00")
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], i+1)
+	}
+
+	// Collect the groups which have more than one row
+	var dups [][]int
+	for _, key := range keys {
+		if len(groups[key]) > 1 {
+			dups = append(dups, groups[key])
+		}
+	}
+
+	return dups
+}
+
+// barBlocks contains the partial blocks of the bars by eighths
+var barBlocks = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// SetBarColumn sets the given column to be printed as bars scaled to the given width
+// The numeric values are printed as proportional bars to the column's maximum value, followed by the value.
+// Non-numeric values are printed as is. A zero width disables the bars.
+func (t *Table) SetBarColumn(col, width int) error {
+
+	if col < 1 || width < 0 {
+		return errors.New("invalid column index or width")
+	}
+
+	if t.barCols == nil {
+		t.barCols = make(map[int]int)
+	}
+
+	if width > 0 {
+		t.barCols[col-1] = width
+	} else {
+		delete(t.barCols, col-1)
+	}
+
+	return nil
+}
+
+// barCell returns the bar of the given value scaled by the given maximum value and width
+func barCell(val string, max float64, width int) string {
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+	if err != nil {
+		return val
+	}
+
+	// Scale the value by eighths of a block
+	var eighths int
+	if max > 0 && n > 0 {
+		eighths = int(n/max*float64(width*8) + 0.5)
+	}
+
+	bar := strings.Repeat("█", eighths/8) + barBlocks[eighths%8]
+
+	return padRight(bar, width) + " " + val
+}
@@ -0,0 +1,113 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli_test
+
+import (
+	"testing"
+
+	"github.com/yieldbot/gocli"
+)
+
+func TestAddTotalsRow(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "foo", "x", "1", "0.10")
+	table.AddRow(2, "bar", "y", "2", "0.2")
+	table.AddRow(3, "baz", "z")
+
+	if err := table.AddTotalsRow("Total", 3, 4); err != nil {
+		t.Fatal(err)
+	}
+
+	var tdata = table.Data()
+	if len(tdata) != 4 {
+		t.Fatal("invalid table data")
+	}
+	if tdata[3][0] != "Total" || tdata[3][1] != "" || tdata[3][2] != "3" || tdata[3][3] != "0.30" {
+		t.Errorf("invalid totals row: %q", tdata[3])
+	}
+
+	if err := table.AddTotalsRow("Total", 2); err == nil {
+		t.Error("invalid numeric value error")
+	}
+
+	if err := table.AddTotalsRow("Total", 1); err == nil {
+		t.Error("invalid column index error")
+	}
+}
+
+func TestSetBarColumn(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "a", "10")
+	table.AddRow(2, "b", "5")
+	table.AddRow(3, "c", "n/a")
+	table.AddRow(4, "d", "1")
+
+	if err := table.SetBarColumn(0, 4); err == nil {
+		t.Error("invalid column index error")
+	}
+
+	table.SetBarColumn(2, 4)
+	table.SetTabWidth(1)
+	out := captureStdout(table.PrintData)
+	if out != "a ████ 10\nb ██   5\nc n/a\nd ▍    1\n" {
+		t.Errorf("invalid table output: %q", out)
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.AddRow(1, "foo", "1")
+	table.AddRow(2, "bar", "2")
+	table.AddRow(3, "foo", "1")
+	table.AddRow(4, "bar", "3")
+
+	dups := table.Duplicates()
+	if len(dups) != 1 || len(dups[0]) != 2 || dups[0][0] != 1 || dups[0][1] != 3 {
+		t.Errorf("invalid duplicates: %v", dups)
+	}
+
+	dups = table.Duplicates(1)
+	if len(dups) != 2 || dups[0][0] != 1 || dups[0][1] != 3 || dups[1][0] != 2 || dups[1][1] != 4 {
+		t.Errorf("invalid duplicates: %v", dups)
+	}
+
+	if dups = table.Duplicates(2); len(dups) != 1 {
+		t.Errorf("invalid duplicates: %v", dups)
+	}
+
+	if len(table.Data()) != 4 {
+		t.Error("invalid table data")
+	}
+}
+
+func TestColStats(t *testing.T) {
+	// Create table
+	var table = gocli.Table{}
+	table.SetHeader("NAME", "SIZE")
+	table.AddRow(1, "foo", "10")
+	table.AddRow(2, "bar", "-2.5")
+	table.AddRow(3, "baz", "")
+	table.AddRow(4, "qux", "4")
+
+	min, max, sum, count, err := table.ColStats(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if min != -2.5 || max != 10 || sum != 11.5 || count != 3 {
+		t.Errorf("invalid column stats: %v %v %v %v", min, max, sum, count)
+	}
+
+	if _, _, _, _, err := table.ColStats(1); err == nil {
+		t.Error("invalid numeric value error")
+	}
+	if _, _, _, _, err := table.ColStats(0); err == nil {
+		t.Error("invalid column index error")
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli
+
+import (
+	"strings"
+)
+
+// maxSuggestDistance is the maximum edit distance for suggestions
+const maxSuggestDistance = 2
+
+// suggest returns the closest candidate to the given input within the maximum edit distance
+// The comparison is case-insensitive
+func suggest(input string, candidates []string) (string, bool) {
+
+	var match string
+	best := maxSuggestDistance + 1
+	for _, c := range candidates {
+		if d := levenshtein(strings.ToLower(input), strings.ToLower(c)); d < best {
+			match, best = c, d
+		}
+	}
+
+	return match, best <= maxSuggestDistance
+}
+
+// SuggestCommand returns the closest command to the given input (i.e. a mistyped command)
+func (cl Cli) SuggestCommand(input string) (string, bool) {
+	return suggest(input, cl.commandNames())
+}
+
+// levenshtein returns the edit distance between the given strings
+func levenshtein(a, b string) int {
+
+	ra, rb := []rune(a), []rune(b)
+
+	// Keep the previous and the current rows of the distance matrix
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// min3 returns the minimum of the given numbers
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
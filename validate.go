@@ -0,0 +1,208 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CheckConfig checks the cli configuration for developer mistakes
+// such as command names that collide with flag names or shadow the built-in help command
+// All the problems found are returned as a single error
+func (cl Cli) CheckConfig() error {
+
+	var errs []string
+
+	// Iterate the commands
+	for _, c := range cl.commandNames() {
+		if strings.TrimSpace(c) == "" {
+			errs = append(errs, "empty command name")
+			continue
+		}
+		if strings.HasPrefix(c, "-") {
+			errs = append(errs, fmt.Sprintf("command %q starts with a dash", c))
+		}
+		if f := flag.Lookup(c); f != nil {
+			errs = append(errs, fmt.Sprintf("command %q collides with flag %s", c, flagName(f.Name)))
+		}
+		if c == "help" {
+			errs = append(errs, fmt.Sprintf("command %q shadows the built-in help command", c))
+		}
+	}
+
+	// Iterate the aliases
+	aliases := make([]string, 0, len(cl.commandAliases))
+	for a := range cl.commandAliases {
+		aliases = append(aliases, a)
+	}
+	sort.Strings(aliases)
+	for _, a := range aliases {
+		if _, ok := cl.Commands[cl.commandAliases[a]]; !ok {
+			errs = append(errs, fmt.Sprintf("alias %q refers to unknown command %q", a, cl.commandAliases[a]))
+		}
+		if _, ok := cl.Commands[a]; ok {
+			errs = append(errs, fmt.Sprintf("alias %q collides with command %q", a, a))
+		}
+	}
+
+	return joinErrors(errs)
+}
+
+// CheckHandlers checks that every command has a handler and every handler has a command
+// All the problems found are returned as a single error
+func (cl Cli) CheckHandlers() error {
+
+	var errs []string
+
+	// Iterate the commands
+	for _, c := range cl.commandNames() {
+		if fn, ok := cl.handlers[c]; !ok || fn == nil {
+			errs = append(errs, fmt.Sprintf("command %q has no handler", c))
+		}
+	}
+
+	// Iterate the handlers
+	names := make([]string, 0, len(cl.handlers))
+	for c := range cl.handlers {
+		names = append(names, c)
+	}
+	sort.Strings(names)
+	for _, c := range names {
+		if _, ok := cl.Commands[c]; !ok {
+			errs = append(errs, fmt.Sprintf("handler of %q has no command", c))
+		}
+	}
+
+	return joinErrors(errs)
+}
+
+// MutuallyExclusive registers the given flags as mutually exclusive
+// Validate reports an error if more than one of them is given
+func (cl *Cli) MutuallyExclusive(names ...string) {
+	cl.exclusiveFlags = append(cl.exclusiveFlags, names)
+}
+
+// RequiredTogether registers the given flags as required together
+// Validate reports an error if some of them are given but not all
+func (cl *Cli) RequiredTogether(names ...string) {
+	cl.togetherFlags = append(cl.togetherFlags, names)
+}
+
+// Validate validates the flags and the subcommand args given on the command line
+// The fixed positional args are checked for the subcommands which have variadic args (see SetVariadic).
+// It should be called after Init and all the problems found are returned as a single error
+func (cl Cli) Validate() error {
+
+	var errs []string
+	set := cl.givenFlags()
+
+	// Check the mutually exclusive flags
+	for _, group := range cl.exclusiveFlags {
+		var given []string
+		for _, name := range group {
+			if set[name] {
+				given = append(given, flagName(name))
+			}
+		}
+		if len(given) > 1 {
+			errs = append(errs, fmt.Sprintf("flags %s are mutually exclusive", strings.Join(given, ", ")))
+		}
+	}
+
+	// Check the flags that are required together
+	for _, group := range cl.togetherFlags {
+		var missing []string
+		for _, name := range group {
+			if !set[name] {
+				missing = append(missing, flagName(name))
+			}
+		}
+		if len(missing) > 0 && len(missing) < len(group) {
+			names := make([]string, len(group))
+			for i, name := range group {
+				names[i] = flagName(name)
+			}
+			errs = append(errs, fmt.Sprintf("flags %s must be given together; missing %s", strings.Join(names, ", "), strings.Join(missing, ", ")))
+		}
+	}
+
+	// Check the required flags of the subcommand
+	var missing []string
+	for _, name := range cl.requiredFlags[cl.command()] {
+		if !set[name] {
+			missing = append(missing, flagName(name))
+		}
+	}
+	// The missing flags and args are kept as a ParseError too for returning it if it's the only error
+	var perr *ParseError
+	if cl.SubCommand != "" && len(missing) > 0 {
+		perr = &ParseError{Err: ErrMissingArg, Name: strings.TrimLeft(missing[0], "-"), msg: fmt.Sprintf("%s: missing required flags: %s", cl.command(), strings.Join(missing, ", "))}
+		errs = append(errs, perr.msg)
+	}
+
+	// Check the fixed positional args of the subcommand if it has a variadic arg
+	if _, ok := cl.variadics[cl.command()]; ok {
+		if fixed := cl.fixedArgs(cl.command()); len(cl.SubCommandPositional) < len(fixed) {
+			missing := fixed[len(cl.SubCommandPositional):]
+			perr = &ParseError{Err: ErrMissingArg, Name: missing[0], msg: fmt.Sprintf("%s: missing args: %s", cl.command(), strings.Join(missing, ", "))}
+			errs = append(errs, perr.msg)
+		}
+	}
+
+	if len(errs) == 1 && perr != nil {
+		return perr
+	}
+
+	return joinErrors(errs)
+}
+
+// RequireFlags registers the given flags as required for the given command
+// Validate reports an error that lists the missing ones if the command is given without them
+func (cl *Cli) RequireFlags(command string, names ...string) {
+
+	if cl.requiredFlags == nil {
+		cl.requiredFlags = make(map[string][]string)
+	}
+	cl.requiredFlags[command] = append(cl.requiredFlags[command], names...)
+}
+
+// givenFlags returns the names of the global flags and the subcommand args
+// that are given on the command line
+func (cl Cli) givenFlags() map[string]bool {
+
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	// If the args are parsed by a flag set then the remaining args are positional
+	if _, ok := cl.flagSets[cl.command()]; ok {
+		for _, name := range cl.ArgOrder {
+			set[name] = true
+		}
+		return set
+	}
+	for _, tok := range cl.tokenizeArgs(cl.SubCommandArgs) {
+		if tok.flag {
+			set[tok.name] = true
+		}
+	}
+
+	return set
+}
+
+// joinErrors returns an error by joining the given error messages
+func joinErrors(errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(errs, "; "))
+}
@@ -0,0 +1,212 @@
+/*
+ * gocli
+ * Copyright (c) 2015 Yieldbot, Inc.
+ * For the full copyright and license information, please view the LICENSE.txt file.
+ */
+
+package gocli_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yieldbot/gocli"
+)
+
+func TestCheckConfig(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Commands: map[string]string{
+			"cmd": "Test command",
+		},
+	}
+	if err := cli.CheckConfig(); err != nil {
+		t.Error("invalid config error")
+	}
+
+	cli = gocli.Cli{
+		Commands: map[string]string{
+			"arg":  "Test command",
+			"-cmd": "Test command",
+		},
+	}
+	err := cli.CheckConfig()
+	if err == nil {
+		t.Fatal("missing config error")
+	}
+	if err.Error() != `command "-cmd" starts with a dash; command "arg" collides with flag --arg` {
+		t.Error("invalid config error")
+	}
+
+	cli = gocli.Cli{
+		Commands: map[string]string{
+			"cmd": "Test command",
+			"c":   "Test command",
+		},
+	}
+	cli.Alias("c", "cmd")
+	cli.Alias("x", "bogus")
+	err = cli.CheckConfig()
+	if err == nil || err.Error() != `alias "c" collides with command "c"; alias "x" refers to unknown command "bogus"` {
+		t.Errorf("invalid config error: %v", err)
+	}
+
+	cli = gocli.Cli{
+		Commands: map[string]string{
+			"help":    "Test command",
+			"version": "Test command",
+		},
+	}
+	err = cli.CheckConfig()
+	if err == nil || !strings.Contains(err.Error(), `command "help" shadows the built-in help command`) || strings.Contains(err.Error(), `command "version" shadows`) {
+		t.Errorf("invalid config error: %v", err)
+	}
+}
+
+func TestCheckHandlers(t *testing.T) {
+
+	var cli = gocli.Cli{}
+	cli.Handle("cmd", "Test command", func(c *gocli.Cli) error {
+		return nil
+	})
+	if err := cli.CheckHandlers(); err != nil {
+		t.Error("invalid handlers error")
+	}
+
+	cli.Handle("old", "Old command", func(c *gocli.Cli) error {
+		return nil
+	})
+	delete(cli.Commands, "old")
+	cli.Commands["new"] = "New command"
+
+	err := cli.CheckHandlers()
+	if err == nil || err.Error() != `command "new" has no handler; handler of "old" has no command` {
+		t.Errorf("invalid handlers error: %v", err)
+	}
+}
+
+func TestMutuallyExclusive(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Commands: map[string]string{
+			"cmd": "Test command",
+		},
+	}
+	cli.MutuallyExclusive("json", "yaml", "xml")
+	cli.MutuallyExclusive("q", "verbose")
+
+	cli.InitArgs([]string{"cmd", "--json", "yaml"})
+	if err := cli.Validate(); err != nil {
+		t.Error("invalid validation error")
+	}
+
+	cli.InitArgs([]string{"cmd", "--json", "--xml=1", "-q", "--verbose"})
+	err := cli.Validate()
+	if err == nil {
+		t.Fatal("missing validation error")
+	}
+	if err.Error() != "flags --json, --xml are mutually exclusive; flags -q, --verbose are mutually exclusive" {
+		t.Errorf("invalid validation error: %s", err)
+	}
+}
+
+func TestRequiredTogether(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Commands: map[string]string{
+			"cmd": "Test command",
+		},
+	}
+	cli.RequiredTogether("username", "password")
+	cli.MutuallyExclusive("json", "yaml")
+
+	cli.InitArgs([]string{"cmd"})
+	if err := cli.Validate(); err != nil {
+		t.Error("invalid validation error")
+	}
+
+	cli.InitArgs([]string{"cmd", "--username", "foo", "--password", "bar"})
+	if err := cli.Validate(); err != nil {
+		t.Error("invalid validation error")
+	}
+
+	cli.InitArgs([]string{"cmd", "--username", "foo", "--json", "--yaml"})
+	err := cli.Validate()
+	if err == nil {
+		t.Fatal("missing validation error")
+	}
+	if err.Error() != "flags --json, --yaml are mutually exclusive; flags --username, --password must be given together; missing --password" {
+		t.Errorf("invalid validation error: %s", err)
+	}
+}
+
+func TestRequireFlags(t *testing.T) {
+
+	var cli = gocli.Cli{
+		Commands: map[string]string{
+			"deploy": "Deploy",
+			"status": "Status",
+		},
+	}
+	cli.RequireFlags("deploy", "env", "region")
+	cli.RequireFlags("deploy", "x")
+
+	cli.InitArgs([]string{"deploy", "--env", "prod"})
+	err := cli.Validate()
+	if err == nil || err.Error() != "deploy: missing required flags: --region, -x" {
+		t.Errorf("invalid required flags error: %v", err)
+	}
+	if perr, ok := err.(*gocli.ParseError); !ok || perr.Err != gocli.ErrMissingArg || perr.Name != "region" {
+		t.Errorf("invalid required flags error: %v", err)
+	}
+
+	cli.InitArgs([]string{"deploy", "--env=prod", "--region", "us", "-x"})
+	if err := cli.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	cli.InitArgs([]string{"status"})
+	if err := cli.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	// Flag set
+	cli.CommandFlags("deploy").String("env", "", "Env")
+	cli.CommandFlags("deploy").String("region", "", "Region")
+	cli.CommandFlags("deploy").Bool("x", false, "X")
+	cli.InitArgs([]string{"deploy", "-env", "prod", "-x", "pos"})
+	if err := cli.Validate(); err == nil || err.Error() != "deploy: missing required flags: --region" {
+		t.Errorf("invalid required flags error: %v", err)
+	}
+}
+
+func TestValidate_variadic(t *testing.T) {
+
+	var cli = gocli.Cli{}
+	cli.AddCommand("copy", "SRC DST", "Copy files")
+	cli.SetVariadic("copy", "FILES")
+
+	cli.InitArgs([]string{"copy", "a"})
+	err := cli.Validate()
+	if err == nil || err.Error() != "copy: missing args: DST" {
+		t.Errorf("invalid missing args error: %v", err)
+	}
+	if perr, ok := err.(*gocli.ParseError); !ok || perr.Err != gocli.ErrMissingArg || perr.Name != "DST" {
+		t.Errorf("invalid missing args error: %v", err)
+	}
+
+	cli.InitArgs([]string{"copy", "a", "b"})
+	if err := cli.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	cli.RequireFlags("copy", "force")
+	cli.InitArgs([]string{"copy", "a"})
+	err = cli.Validate()
+	if err == nil || err.Error() != "copy: missing required flags: --force; copy: missing args: DST" {
+		t.Errorf("invalid missing flags and args error: %v", err)
+	}
+	if _, ok := err.(*gocli.ParseError); ok {
+		t.Errorf("invalid missing flags and args error: %v", err)
+	}
+}